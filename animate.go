@@ -0,0 +1,113 @@
+package templheroicons
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// Animation represents an animation preset applied to the icon.
+type Animation int
+
+const (
+	NoAnimation Animation = iota // No animation (default)
+	Spin                         // Continuous rotation, e.g. for loading spinners
+	Pulse                        // Fading in and out
+	Bounce                       // Bouncing up and down
+)
+
+// String returns the name of the animation preset.
+func (a Animation) String() string {
+	switch a {
+	case Spin:
+		return "spin"
+	case Pulse:
+		return "pulse"
+	case Bounce:
+		return "bounce"
+	default:
+		return "none"
+	}
+}
+
+// animationKeyframes holds the CSS keyframes and timing of each animation preset.
+var animationKeyframes = map[Animation]struct {
+	keyframes string
+	timing    string
+}{
+	Spin:   {keyframes: `{to{transform:rotate(360deg)}}`, timing: "linear"},
+	Pulse:  {keyframes: `{50%{opacity:.5}}`, timing: "cubic-bezier(.4,0,.6,1)"},
+	Bounce: {keyframes: `{0%,100%{transform:translateY(-25%);animation-timing-function:cubic-bezier(.8,0,1,1)}50%{transform:none;animation-timing-function:cubic-bezier(0,0,.2,1)}}`, timing: "ease"},
+}
+
+// Animation durations are rounded to steps and capped, bounding the number of CSS rules.
+const (
+	animationStep        = 50 * time.Millisecond
+	maxAnimationDuration = 10 * time.Second
+)
+
+// Animate applies an animation preset to the icon, repeating every duration
+// (e.g., Animate(Spin, time.Second) for a loading spinner). The duration is rounded
+// to 50ms and capped at 10s.
+// The CSS rules are rendered once per page in a <style> tag using the nonce set with
+// templ.WithNonce, and the animation is disabled when the user prefers reduced motion.
+// The animation needs the inline <svg>: icons embedded as data URIs or in <img> tags
+// are not animated, since the page styles do not apply to them.
+func (b *IconBuilder) Animate(animation Animation, duration time.Duration) *IconBuilder {
+	if _, ok := animationKeyframes[animation]; !ok {
+		b.icon.setError(fmt.Errorf("invalid animation %d", animation))
+		return b
+	}
+	if duration < time.Millisecond {
+		b.icon.setError(fmt.Errorf("invalid animation duration %s", duration))
+		return b
+	}
+	b.icon.animation = animation
+	b.icon.animationDuration = min(max(duration.Round(animationStep), animationStep), maxAnimationDuration)
+	return b
+}
+
+// animationClass returns the CSS class animating the icon, or an empty string.
+func animationClass(icon *Icon) string {
+	if icon.animation == NoAnimation {
+		return ""
+	}
+	return fmt.Sprintf("templheroicons-%s-%dms", icon.animation, icon.animationDuration.Milliseconds())
+}
+
+// animationStyles holds a once handle per animation class, so that each <style> tag
+// is rendered once per page. Rounded durations keep it to a few hundred handles.
+var animationStyles sync.Map
+
+// renderAnimationStyle renders the <style> tag of the icon animation, once per context.
+func renderAnimationStyle(ctx context.Context, w io.Writer, icon *Icon) error {
+	class := animationClass(icon)
+	if class == "" {
+		return nil
+	}
+
+	handle, _ := animationStyles.LoadOrStore(class, templ.NewOnceHandle(templ.WithComponent(
+		templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, makeAnimationStyle(ctx, icon.animation, class, icon.animationDuration))
+			return err
+		}),
+	)))
+	return handle.(*templ.OnceHandle).Once().Render(ctx, w)
+}
+
+// makeAnimationStyle generates the <style> tag with the keyframes and the class of the animation.
+func makeAnimationStyle(ctx context.Context, animation Animation, class string, duration time.Duration) string {
+	var nonceAttr string
+	if nonce := templ.GetNonce(ctx); nonce != "" {
+		nonceAttr = fmt.Sprintf(` nonce="%s"`, templ.EscapeString(nonce))
+	}
+
+	preset := animationKeyframes[animation]
+	name := "templheroicons-" + animation.String()
+	return fmt.Sprintf(`<style%[1]s>@keyframes %[2]s%[3]s.%[4]s{animation:%[2]s %[5]dms %[6]s infinite;transform-origin:center;transform-box:fill-box}@media (prefers-reduced-motion:reduce){.%[4]s{animation:none}}</style>`,
+		nonceAttr, name, preset.keyframes, class, duration.Milliseconds(), preset.timing)
+}
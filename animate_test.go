@@ -0,0 +1,117 @@
+package templheroicons
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+func TestAnimate_Builder(t *testing.T) {
+	icon := &Icon{Name: "arrow-path", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	result := makeSVGTag(icon.Config().Animate(Spin, time.Second).GetIcon())
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor" class="templheroicons-spin-1000ms"><path d="M1 1"/></svg>`
+	if result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+
+	// The animation class is appended to the user-defined class
+	result = makeSVGTag(icon.Config().SetAttrs(templ.Attributes{"class": "size-6"}).Animate(Pulse, 2*time.Second).GetIcon())
+	expected = `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor" class="size-6 templheroicons-pulse-2000ms"><path d="M1 1"/></svg>`
+	if result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+
+	if icon.animation != NoAnimation {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestAnimate_InvalidValues(t *testing.T) {
+	icon := &Icon{Name: "arrow-path", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name          string
+		builder       *IconBuilder
+		expectedError string
+	}{
+		{"Unknown animation", icon.Config().Animate(Animation(42), time.Second), "invalid animation 42"},
+		{"No animation", icon.Config().Animate(NoAnimation, time.Second), "invalid animation 0"},
+		{"Zero duration", icon.Config().Animate(Spin, 0), "invalid animation duration 0s"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expectedError) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestAnimate_RoundedDuration(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		expected string
+	}{
+		{"Kept on a step", 750 * time.Millisecond, "templheroicons-spin-750ms"},
+		{"Rounded to the closest step", 1234 * time.Millisecond, "templheroicons-spin-1250ms"},
+		{"Rounded up to the first step", time.Millisecond, "templheroicons-spin-50ms"},
+		{"Capped", time.Hour, "templheroicons-spin-10000ms"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if class := animationClass(Sun.Config().Animate(Spin, tt.duration).GetIcon()); class != tt.expected {
+				t.Errorf("animationClass() = %q, want %q", class, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAnimate_RenderStyleOncePerPage(t *testing.T) {
+	icon := &Icon{Name: "arrow-path", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+	spinner := icon.Config().Animate(Bounce, 750*time.Millisecond)
+
+	ctx := templ.WithNonce(templ.InitializeContext(context.Background()), "r4nd0m")
+
+	var builder strings.Builder
+	for range 2 {
+		if err := spinner.Render().Render(ctx, &builder); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	result := builder.String()
+
+	if count := strings.Count(result, "<style"); count != 1 {
+		t.Errorf("expected the style to be rendered once, got %d in %q", count, result)
+	}
+	if !strings.HasPrefix(result, `<style nonce="r4nd0m">@keyframes templheroicons-bounce{`) {
+		t.Errorf("expected a style with nonce, got %q", result)
+	}
+	for _, rule := range []string{
+		`.templheroicons-bounce-750ms{animation:templheroicons-bounce 750ms ease infinite;`,
+		`@media (prefers-reduced-motion:reduce){.templheroicons-bounce-750ms{animation:none}}`,
+	} {
+		if !strings.Contains(result, rule) {
+			t.Errorf("expected %q in %q", rule, result)
+		}
+	}
+	if count := strings.Count(result, `class="templheroicons-bounce-750ms"`); count != 2 {
+		t.Errorf("expected 2 animated icons, got %d", count)
+	}
+
+	// A new page renders the style again
+	builder.Reset()
+	if err := spinner.Render().Render(templ.InitializeContext(context.Background()), &builder); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(builder.String(), "<style>") {
+		t.Errorf("expected a style without nonce, got %q", builder.String())
+	}
+}
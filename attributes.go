@@ -0,0 +1,147 @@
+package templheroicons
+
+import (
+	"html"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// AttributePolicy defines which custom attributes are written to the <svg> tag.
+//
+// Attribute names are always validated against the HTML specification, and
+// values are always HTML-escaped, so an attribute can never break out of the
+// <svg> opening tag. The policy decides which valid names are accepted:
+//
+//   - Event handlers (`on*`) with string values must be listed in EventHandlers;
+//     templ script handlers are accepted on any event attribute.
+//   - Names starting with one of the Prefixes are accepted (e.g., `hx-get`,
+//     `data-id`, `x-on:click`, `@click`, `:class`).
+//   - Any other name is accepted if listed in Names, or if AllowAnyName is true.
+type AttributePolicy struct {
+	EventHandlers []string // Event attributes allowed with string values (e.g., "onclick")
+	Prefixes      []string // Framework prefixes (e.g., "hx-", "x-", "@")
+	Names         []string // Additional attribute names allowed
+	AllowAnyName  bool     // Allow any valid attribute name not covered above
+}
+
+// DefaultAttributePolicy returns the policy used when none is set.
+// It allows aria, data, htmx and Alpine.js attributes, any other valid name,
+// and the `onclick`, `onchange` and `onhover` inline handlers.
+func DefaultAttributePolicy() AttributePolicy {
+	return AttributePolicy{
+		EventHandlers: []string{"onclick", "onchange", "onhover"},
+		Prefixes:      []string{"aria-", "data-", "hx-", "x-", "@", ":"},
+		AllowAnyName:  true,
+	}
+}
+
+// Active attribute policy, shared by all renders
+var (
+	attributePolicy      = DefaultAttributePolicy()
+	attributePolicyMutex sync.RWMutex
+)
+
+// SetAttributePolicy replaces the attribute policy used by all icons.
+func SetAttributePolicy(policy AttributePolicy) {
+	attributePolicyMutex.Lock()
+	defer attributePolicyMutex.Unlock()
+	attributePolicy = policy
+}
+
+// currentAttributePolicy returns the attribute policy in use.
+func currentAttributePolicy() AttributePolicy {
+	attributePolicyMutex.RLock()
+	defer attributePolicyMutex.RUnlock()
+	return attributePolicy
+}
+
+// allowsName reports whether the policy accepts a (valid) non-event attribute name.
+func (p AttributePolicy) allowsName(key string) bool {
+	if p.AllowAnyName {
+		return true
+	}
+	lowerKey := strings.ToLower(key)
+	for _, prefix := range p.Prefixes {
+		if strings.HasPrefix(lowerKey, strings.ToLower(prefix)) {
+			return true
+		}
+	}
+	for _, name := range p.Names {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// allowsEventHandler reports whether the policy accepts a string value for the event attribute.
+func (p AttributePolicy) allowsEventHandler(key string) bool {
+	for _, name := range p.EventHandlers {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidAttributeName reports whether the key is a valid HTML attribute name.
+// Per the HTML specification, names cannot contain controls, spaces, `"`, `'`,
+// `>`, `/`, `=` or noncharacters; `<` is rejected too since it is a parse error.
+func isValidAttributeName(key string) bool {
+	if key == "" || !utf8.ValidString(key) {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case unicode.IsControl(r), unicode.IsSpace(r), isNoncharacter(r):
+			return false
+		case strings.ContainsRune("\"'>/=<", r):
+			return false
+		}
+	}
+	return true
+}
+
+// isNoncharacter reports whether r is a Unicode noncharacter.
+func isNoncharacter(r rune) bool {
+	return (r >= 0xFDD0 && r <= 0xFDEF) || r&0xFFFE == 0xFFFE
+}
+
+// isEventAttribute reports whether the attribute key is a JS event handler (e.g., `onclick`).
+func isEventAttribute(key string) bool {
+	if len(key) <= 2 || !strings.HasPrefix(strings.ToLower(key), "on") {
+		return false
+	}
+	for _, r := range key[2:] {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// sanitizeAttribute ensures that attribute keys and values are safe for inclusion in the SVG tag.
+// Keys are validated rather than escaped, so framework syntax such as `@click` is kept as is.
+func sanitizeAttribute(key, value string) (string, string, bool) {
+	if !isValidAttributeName(key) {
+		return "", "", false
+	}
+
+	policy := currentAttributePolicy()
+	if isEventAttribute(key) {
+		if !policy.allowsEventHandler(key) {
+			return "", "", false
+		}
+		// For event attributes, only allow simple JS functions (no <script> tags, eval, etc.)
+		if strings.Contains(strings.ToLower(value), "<script>") || strings.Contains(strings.ToLower(value), "javascript:") {
+			return "", "", false // Unsafe value
+		}
+	} else if !policy.allowsName(key) {
+		return "", "", false
+	}
+
+	// Escape any unsafe characters in the value
+	return key, html.EscapeString(value), true // Safe attribute
+}
@@ -0,0 +1,191 @@
+package templheroicons
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestAttributes_isValidAttributeName(t *testing.T) {
+	tests := []struct {
+		key   string
+		valid bool
+	}{
+		{"aria-label", true},
+		{"hx-get", true},
+		{"@click.prevent", true},
+		{"x-on:click", true},
+		{":class", true},
+		{"data-ünicode", true},
+		{"", false},
+		{"a b", false},
+		{`a"b`, false},
+		{"a'b", false},
+		{"a>b", false},
+		{"a<b", false},
+		{"a/b", false},
+		{"a=b", false},
+		{"a\x00b", false},
+		{"a\u0085b", false},
+		{"a﷐b", false},
+		{"a￾b", false},
+		{"a\xffb", false}, // Invalid UTF-8
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.key), func(t *testing.T) {
+			if got := isValidAttributeName(tt.key); got != tt.valid {
+				t.Errorf("isValidAttributeName(%q) = %v, want %v", tt.key, got, tt.valid)
+			}
+		})
+	}
+}
+
+func TestAttributes_isEventAttribute(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected bool
+	}{
+		{"onclick", true},
+		{"OnMouseOver", true},
+		{"on", false},
+		{"on-boarding", false},
+		{"hx-on:click", false},
+		{"x-on:click", false},
+		{"class", false},
+	}
+
+	for _, tt := range tests {
+		if got := isEventAttribute(tt.key); got != tt.expected {
+			t.Errorf("isEventAttribute(%q) = %v, want %v", tt.key, got, tt.expected)
+		}
+	}
+}
+
+func TestAttributes_DefaultPolicy(t *testing.T) {
+	attrs := templ.Attributes{
+		"@click":           "open = !open",
+		":class":           "{ 'text-red-500': open }",
+		"x-on:mouseenter":  "hover = true",
+		"hx-get":           "/menu?a=1&b=2",
+		"data-id":          `a"b`,
+		"onclick":          "toggle()",
+		"onmouseover":      "track()", // Not in the default allowlist
+		"bad name":         "x",
+		`x"><script>`:      "x",
+		"class":            "size-6",
+		"data-testid":      "icon",
+		"aria-describedby": "tip",
+	}
+
+	var builder strings.Builder
+	addAttributesToSVG(&builder, attrs)
+
+	expected := ` :class="{ &#39;text-red-500&#39;: open }" @click="open = !open" aria-describedby="tip" class="size-6" data-id="a&#34;b" data-testid="icon" hx-get="/menu?a=1&amp;b=2" onclick="toggle()" x-on:mouseenter="hover = true"`
+	if builder.String() != expected {
+		t.Errorf("addAttributesToSVG() = %q, want %q", builder.String(), expected)
+	}
+}
+
+func TestAttributes_SetAttributePolicy(t *testing.T) {
+	defer SetAttributePolicy(DefaultAttributePolicy())
+
+	SetAttributePolicy(AttributePolicy{
+		EventHandlers: []string{"onmouseover"},
+		Prefixes:      []string{"hx-"},
+		Names:         []string{"class"},
+	})
+
+	attrs := templ.Attributes{
+		"class":       "size-6",
+		"hx-post":     "/save",
+		"data-id":     "1",
+		"@click":      "open = true",
+		"onclick":     "toggle()",
+		"onmouseover": "track()",
+	}
+
+	var builder strings.Builder
+	addAttributesToSVG(&builder, attrs)
+
+	expected := ` class="size-6" hx-post="/save" onmouseover="track()"`
+	if builder.String() != expected {
+		t.Errorf("addAttributesToSVG() = %q, want %q", builder.String(), expected)
+	}
+}
+
+// FuzzAttributes_addAttributesToSVG ensures that no attribute key or value can break
+// out of the <svg> opening tag or inject attributes other than the one provided.
+func FuzzAttributes_addAttributesToSVG(f *testing.F) {
+	seeds := [][2]string{
+		{"class", "icon"},
+		{"@click", "open = !open"},
+		{"onclick", `alert("x")`},
+		{`x"><script>alert(1)</script>`, "v"},
+		{"title", `"><script>alert(1)</script>`},
+		{"data-x", "' onload='alert(1)"},
+		{"a b", "c"},
+		{"on\x00click", "x"},
+		{"x=y", "z"},
+		{"href", "javascript:alert(1)"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, key, value string) {
+		var builder strings.Builder
+		addAttributesToSVG(&builder, templ.Attributes{key: value})
+		tag := "<svg" + builder.String() + ">"
+
+		names, err := parseOpeningTag(tag)
+		if err != nil {
+			t.Fatalf("malformed tag %q: %v", tag, err)
+		}
+		if len(names) > 1 || (len(names) == 1 && names[0] != key) {
+			t.Fatalf("attribute injection: key %q produced attributes %q in %q", key, names, tag)
+		}
+	})
+}
+
+// parseOpeningTag parses an `<svg ...>` opening tag made of double-quoted attributes
+// and returns the attribute names. It fails if the tag does not end at the final `>`.
+func parseOpeningTag(tag string) ([]string, error) {
+	rest, ok := strings.CutPrefix(tag, "<svg")
+	if !ok {
+		return nil, fmt.Errorf("missing <svg prefix")
+	}
+
+	var names []string
+	for {
+		if rest == ">" {
+			return names, nil
+		}
+		if !strings.HasPrefix(rest, " ") {
+			return nil, fmt.Errorf("expected space at %q", rest)
+		}
+		rest = rest[1:]
+
+		eq := strings.Index(rest, `="`)
+		if eq <= 0 {
+			return nil, fmt.Errorf("expected attribute at %q", rest)
+		}
+		name := rest[:eq]
+		if strings.ContainsAny(name, " \t\n\f\r\"'<>/=") {
+			return nil, fmt.Errorf("invalid attribute name %q", name)
+		}
+		rest = rest[eq+2:]
+
+		end := strings.IndexByte(rest, '"')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated value for %q", name)
+		}
+		if strings.ContainsAny(rest[:end], "<>") {
+			return nil, fmt.Errorf("unescaped value for %q", name)
+		}
+		names = append(names, name)
+		rest = rest[end+1:]
+	}
+}
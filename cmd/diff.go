@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// diffOp is a line of a line-based diff.
+type diffOp struct {
+	kind byte // ' ' for unchanged lines, '-' for removed lines, '+' for added lines
+	line string
+}
+
+// unifiedDiff returns a unified diff turning the old text into the new one,
+// or an empty string if they are equal.
+func unifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+	ops := diffLines(splitLines(oldText), splitLines(newText))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "--- %s\n+++ %s\n", oldName, newName)
+	for start := 0; start < len(ops); {
+		// Find the next change, then extend the hunk while changes are close enough
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		last := first
+		for next := first; next < len(ops); next++ {
+			if ops[next].kind == ' ' {
+				continue
+			}
+			if next-last > 2*diffContext {
+				break
+			}
+			last = next
+		}
+		from := max(first-diffContext, start)
+		to := min(last+diffContext+1, len(ops))
+		writeHunk(&builder, ops, from, to)
+		start = to
+	}
+	return builder.String()
+}
+
+// writeHunk writes the operations in [from, to) as a unified diff hunk.
+func writeHunk(builder *strings.Builder, ops []diffOp, from, to int) {
+	oldLine, newLine := 1, 1
+	for _, op := range ops[:from] {
+		if op.kind != '+' {
+			oldLine++
+		}
+		if op.kind != '-' {
+			newLine++
+		}
+	}
+	var oldCount, newCount int
+	for _, op := range ops[from:to] {
+		if op.kind != '+' {
+			oldCount++
+		}
+		if op.kind != '-' {
+			newCount++
+		}
+	}
+	fmt.Fprintf(builder, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+	for _, op := range ops[from:to] {
+		builder.WriteByte(op.kind)
+		builder.WriteString(op.line)
+		builder.WriteByte('\n')
+	}
+}
+
+// hunkRange formats the start and length of a hunk side.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start-- // An empty side points at the line before the hunk
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// splitLines splits a text into lines, without the line terminators.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines computes a line-based diff from the longest common subsequence of the lines.
+// Common leading and trailing lines are skipped first, which keeps regenerated files cheap to compare.
+func diffLines(oldLines, newLines []string) []diffOp {
+	prefix := 0
+	for prefix < len(oldLines) && prefix < len(newLines) && oldLines[prefix] == newLines[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(oldLines)-prefix && suffix < len(newLines)-prefix &&
+		oldLines[len(oldLines)-1-suffix] == newLines[len(newLines)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(oldLines)+len(newLines))
+	for _, line := range oldLines[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = diffMiddle(ops, oldLines[prefix:len(oldLines)-suffix], newLines[prefix:len(newLines)-suffix])
+	for _, line := range oldLines[len(oldLines)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	return ops
+}
+
+// diffMiddle appends the diff of the lines to the operations, splitting the old lines in
+// halves around the longest common subsequence (Hirschberg), so that memory stays linear
+// in the number of lines. Removed lines come before added lines.
+func diffMiddle(ops []diffOp, a, b []string) []diffOp {
+	switch {
+	case len(a) == 0:
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	case len(b) == 0:
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		return ops
+	case len(a) == 1:
+		for j, line := range b {
+			if line == a[0] {
+				ops = diffMiddle(ops, nil, b[:j])
+				ops = append(ops, diffOp{' ', line})
+				return diffMiddle(ops, nil, b[j+1:])
+			}
+		}
+		ops = append(ops, diffOp{'-', a[0]})
+		return diffMiddle(ops, nil, b)
+	}
+
+	// Split b where the common subsequences of both halves of a are the longest
+	mid := len(a) / 2
+	forward := lcsLengths(a[:mid], b, false)
+	backward := lcsLengths(a[mid:], b, true)
+	split, best := 0, -1
+	for j := 0; j <= len(b); j++ {
+		if length := forward[j] + backward[len(b)-j]; length > best {
+			split, best = j, length
+		}
+	}
+	ops = diffMiddle(ops, a[:mid], b[:split])
+	return diffMiddle(ops, a[mid:], b[split:])
+}
+
+// lcsLengths returns the lengths of the longest common subsequences of a with each prefix
+// of b (with each suffix of b, indexed by length, if reversed), keeping two rows in memory.
+func lcsLengths(a, b []string, reversed bool) []int {
+	previous, current := make([]int, len(b)+1), make([]int, len(b)+1)
+	for i := range a {
+		lineA := a[i]
+		if reversed {
+			lineA = a[len(a)-1-i]
+		}
+		for j := 1; j <= len(b); j++ {
+			lineB := b[j-1]
+			if reversed {
+				lineB = b[len(b)-j]
+			}
+			if lineA == lineB {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "Equal texts",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name: "Changed line",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n",
+			new:  "1\n2\n3\n4\nfive\n6\n7\n8\n",
+			expected: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n" +
+				" 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+		},
+		{
+			name: "Distant changes make separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n" +
+				"@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+		{
+			name:     "Added lines",
+			old:      "a\nc\n",
+			new:      "a\nb\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,2 +1,3 @@\n a\n+b\n c\n",
+		},
+		{
+			name:     "New file",
+			old:      "",
+			new:      "a\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1 @@\n+a\n",
+		},
+		{
+			name:     "Removed file",
+			old:      "a\nb\n",
+			new:      "",
+			expected: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unifiedDiff("old", "new", tt.old, tt.new); got != tt.expected {
+				t.Errorf("unifiedDiff() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	// A header change plus a trailing block leaves no common prefix or suffix to skip
+	oldLines := make([]string, 0, 6000)
+	newLines := make([]string, 0, 6010)
+	newLines = append(newLines, "// new header")
+	oldLines = append(oldLines, "// old header")
+	for i := range 6000 {
+		line := fmt.Sprintf("line %d", i)
+		oldLines = append(oldLines, line)
+		if i%1000 != 500 {
+			newLines = append(newLines, line)
+		}
+	}
+	newLines = append(newLines, "// trailing block")
+
+	// A quadratic matrix would allocate about 290 MB
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	ops := diffLines(oldLines, newLines)
+	runtime.ReadMemStats(&after)
+	if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 16<<20 {
+		t.Errorf("diffLines() allocated %d bytes, want linear memory", allocated)
+	}
+
+	// The operations turn the old lines into the new ones, keeping every common line
+	var gotOld, gotNew []string
+	common := 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			gotOld = append(gotOld, op.line)
+		}
+		if op.kind != '-' {
+			gotNew = append(gotNew, op.line)
+		}
+		if op.kind == ' ' {
+			common++
+		}
+	}
+	if !slices.Equal(gotOld, oldLines) || !slices.Equal(gotNew, newLines) {
+		t.Fatal("diffLines() does not reconstruct the texts")
+	}
+	if common != 5994 {
+		t.Errorf("diffLines() kept %d common lines, want 5994", common)
+	}
+}
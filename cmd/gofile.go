@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"go/format"
+	"os"
+	"sort"
+	"text/template"
+)
+
+// goFileTemplate is the template of the Go file with icon definitions.
+var goFileTemplate = template.Must(template.New("gofile").Parse(`// Code generated by 'cmd/icons-maker.go'; DO NOT EDIT.
+
+package {{ .Package }}
+{{- if .External }}
+
+import "github.com/indaco/templheroicons"
+{{- end }}
+
+var (
+{{- range $i, $icon := .Icons }}
+{{- if $i }}
+{{ end }}
+	// {{ .StructName }} is the {{ .Type }} variant of the "{{ .BaseName }}" icon, {{ .Width }}x{{ .Height }}.
+	//
+	// ![{{ .Name }}]({{ .Preview }})
+	{{ template "icon" . }}
+{{- end }}
+)
+{{- if .Deprecated }}
+
+// Icons removed from the dataset, kept until its next major version.
+var (
+{{- range $i, $icon := .Deprecated }}
+{{- if $i }}
+{{ end }}
+	// {{ .StructName }} is the {{ .Type }} variant of the "{{ .BaseName }}" icon, {{ .Width }}x{{ .Height }}.
+	//
+	// ![{{ .Name }}]({{ .Preview }})
+	//
+{{- if .Replacement }}
+	// Deprecated: Removed in {{ $.Prefix }} v{{ .RemovedIn }}, use {{ .Replacement }} instead.
+	{{ .StructName }} = {{ .Replacement }}
+{{- else }}
+	// Deprecated: Removed in {{ $.Prefix }} v{{ .RemovedIn }}, without replacement.
+	{{ template "icon" . }}
+{{- end }}
+{{- end }}
+)
+{{- end }}
+{{- define "icon" }}
+{{- if .External -}}
+{{ .StructName }} = templheroicons.NewIcon({{ printf "%q" .Name }}, {{ printf "%q" .Type }}, {{ .Width }}, {{ printf "%q" .Body }})
+{{- else -}}
+{{ .StructName }} = &Icon{Name: {{ printf "%q" .Name }}, Type: {{ printf "%q" .Type }}, Size: {{ printf "%q" .Size }}{{ if .Directional }}, Directional: true{{ end }}{{ if .Body }}, body: {{ printf "%q" .Body }}{{ end }}}
+{{- end }}
+{{- end }}
+`))
+
+// goFileIcon holds the fields of an icon definition in the template.
+type goFileIcon struct {
+	StructName  string
+	Name        string
+	BaseName    string
+	Type        string
+	Size        string
+	Directional bool
+	Width       int
+	Height      int
+	Preview     string
+	External    bool   // Whether the icon is created with NewIcon, outside the templheroicons package
+	Body        string // Body of removed icons, and of all icons created with NewIcon
+	RemovedIn   string // Dataset version removing the icon
+	Replacement string // Variable name of the icon replacing a removed icon
+}
+
+// Generates a Go file with icon definitions.
+func generateGoFile(cfg config, icons map[string]*generatedIcon, shims []*deprecatedIcon) error {
+	source, err := renderGoFile(cfg, icons, shims)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(cfg.outputFilePath(), source, 0644)
+}
+
+// Renders the gofmt-formatted Go source with icon definitions, sorted by variable name,
+// followed by the deprecated shims of removed icons. The heroicons package references the
+// embedded dataset; other packages embed the icon bodies and create icons with NewIcon.
+func renderGoFile(cfg config, icons map[string]*generatedIcon, shims []*deprecatedIcon) ([]byte, error) {
+	data := struct {
+		Package    string
+		Prefix     string
+		External   bool
+		Icons      []goFileIcon
+		Deprecated []goFileIcon
+	}{
+		Package:  cfg.pkg,
+		Prefix:   cfg.set.prefix,
+		External: !cfg.builtin(),
+	}
+	for _, generated := range icons {
+		icon := newGoFileIcon(generated)
+		if data.External {
+			icon.External = true
+			icon.Body = generated.body
+		}
+		data.Icons = append(data.Icons, icon)
+	}
+	for _, shim := range shims {
+		icon := newGoFileIcon(shim.generatedIcon)
+		icon.External = data.External
+		icon.Body = shim.body
+		icon.RemovedIn = shim.removedIn
+		icon.Replacement = shim.replacement
+		data.Deprecated = append(data.Deprecated, icon)
+	}
+	sort.Slice(data.Icons, func(i, j int) bool {
+		return data.Icons[i].StructName < data.Icons[j].StructName
+	})
+
+	var buffer bytes.Buffer
+	if err := goFileTemplate.Execute(&buffer, data); err != nil {
+		return nil, err
+	}
+	source, err := format.Source(buffer.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return source, nil
+}
+
+// newGoFileIcon returns the template fields of an icon.
+func newGoFileIcon(generated *generatedIcon) goFileIcon {
+	icon := generated.icon
+	return goFileIcon{
+		StructName:  generated.structName,
+		Name:        icon.Name,
+		BaseName:    generated.baseName,
+		Type:        icon.Type,
+		Size:        icon.Size.String(),
+		Directional: icon.Directional,
+		Width:       generated.width,
+		Height:      generated.height,
+		Preview:     previewDataURI(generated),
+	}
+}
+
+// previewDataURI returns the icon as a base64 SVG data URI, shown by gopls hovers.
+func previewDataURI(generated *generatedIcon) string {
+	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="%[2]d" viewBox="0 0 %[1]d %[2]d">%[3]s</svg>`,
+		generated.width, generated.height, generated.body)
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(svg))
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/base64"
+	"go/format"
+	"strings"
+	"testing"
+
+	heroicons "github.com/indaco/templheroicons"
+)
+
+func TestRenderGoFile(t *testing.T) {
+	icons := map[string]*generatedIcon{
+		"moon-20-solid": {
+			icon:       &heroicons.Icon{Name: "moon-20-solid", Type: "Mini", Size: heroicons.Size20},
+			structName: "MoonMini",
+			baseName:   "moon",
+			body:       `<path d="M1 1"/>`,
+			width:      20,
+			height:     20,
+		},
+		"arrow-left": {
+			icon:       &heroicons.Icon{Name: "arrow-left", Type: "Outline", Size: heroicons.Size24, Directional: true},
+			structName: "ArrowLeft",
+			baseName:   "arrow-left",
+			body:       `<path d="M2 2"/>`,
+			width:      24,
+			height:     24,
+		},
+	}
+
+	source, err := renderGoFile(config{pkg: packageName, set: heroiconsSet}, icons, nil)
+	if err != nil {
+		t.Fatalf("renderGoFile() error = %v", err)
+	}
+
+	arrowPreview := base64.StdEncoding.EncodeToString([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24"><path d="M2 2"/></svg>`))
+	moonPreview := base64.StdEncoding.EncodeToString([]byte(`<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20"><path d="M1 1"/></svg>`))
+	expected := `// Code generated by 'cmd/icons-maker.go'; DO NOT EDIT.
+
+package templheroicons
+
+var (
+	// ArrowLeft is the Outline variant of the "arrow-left" icon, 24x24.
+	//
+	// ![arrow-left](data:image/svg+xml;base64,` + arrowPreview + `)
+	ArrowLeft = &Icon{Name: "arrow-left", Type: "Outline", Size: "24", Directional: true}
+
+	// MoonMini is the Mini variant of the "moon" icon, 20x20.
+	//
+	// ![moon-20-solid](data:image/svg+xml;base64,` + moonPreview + `)
+	MoonMini = &Icon{Name: "moon-20-solid", Type: "Mini", Size: "20"}
+)
+`
+	if string(source) != expected {
+		t.Errorf("renderGoFile() = %q, want %q", source, expected)
+	}
+
+	formatted, err := format.Source(source)
+	if err != nil {
+		t.Fatalf("format.Source() error = %v", err)
+	}
+	if string(formatted) != string(source) {
+		t.Errorf("renderGoFile() is not gofmt-formatted")
+	}
+}
+
+func TestRenderGoFileEscapesNames(t *testing.T) {
+	icons := map[string]*generatedIcon{
+		`odd"name`: {
+			icon:       &heroicons.Icon{Name: `odd"name`, Type: "Outline", Size: heroicons.Size24},
+			structName: "OddName",
+			baseName:   `odd"name`,
+			width:      24,
+			height:     24,
+		},
+	}
+
+	source, err := renderGoFile(config{pkg: packageName, set: heroiconsSet}, icons, nil)
+	if err != nil {
+		t.Fatalf("renderGoFile() error = %v", err)
+	}
+	if !strings.Contains(string(source), `OddName = &Icon{Name: "odd\"name"`) {
+		t.Errorf("renderGoFile() = %q, want the name quoted", source)
+	}
+}
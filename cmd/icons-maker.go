@@ -1,15 +1,20 @@
 package main
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
+	"go/token"
 	"io"
 	"log"
 	"net/http"
 	"os"
-	"path"
-	"sort"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	heroicons "github.com/indaco/templheroicons"
 	"github.com/tidwall/gjson"
@@ -17,18 +22,194 @@ import (
 
 // Constants
 const (
-	Size16 heroicons.Size = "16"
-	Size20 heroicons.Size = "20"
-	Size24 heroicons.Size = "24"
-
 	cacheDuration = 30 * 24 * time.Hour
-	datasetURL    = "https://raw.githubusercontent.com/iconify/icon-sets/refs/heads/master/json/heroicons.json"
+	datasetURL    = "https://raw.githubusercontent.com/iconify/icon-sets/refs/heads/master/json/%s.json"
 	maxRetries    = 3
 	retryDelay    = 5 * time.Second
-	cacheFile     = "heroicons_cache.json"
-	outputFile    = "heroicons_generated.go"
+	cacheFile     = "%s_cache.json"
+	outputFile    = "%s_generated.go"
+	packageName   = "templheroicons"
 )
 
+// errNoIcons is returned when the dataset has no icons, e.g. a truncated download.
+var errNoIcons = errors.New("no icons found in JSON data")
+
+// errOutdated is returned in check mode when the generated file does not match the dataset.
+var errOutdated = errors.New("generated file is out of date, run the build task to regenerate it")
+
+// config holds the icons-maker options.
+type config struct {
+	input      string        // Dataset URL or local file path
+	cachePath  string        // Cache file of a dataset downloaded from a URL
+	outputDir  string        // Directory of the generated Go file
+	pkg        string        // Package name of the generated Go file
+	cacheTTL   time.Duration // Maximum age of the cache before fetching again
+	offline    bool          // Never fetch the dataset, use the cache as is
+	forceFetch bool          // Fetch the dataset even if the cache is fresh
+	check      bool          // Verify the generated file instead of writing it
+	report     string        // Markdown report of the dataset changes, "-" for stdout
+	changie    string        // Directory of the changie entries of the dataset changes
+	compare    string        // Previous dataset, defaults to the cache before fetching
+	manifest   string        // Manifest of the generated icons, empty to disable deprecation shims
+	set        *iconSet      // Icon set of the dataset
+}
+
+// reports reports whether the dataset changes are reported, as markdown or changie entries.
+func (cfg config) reports() bool {
+	return cfg.report != "" || cfg.changie != ""
+}
+
+// builtin reports whether the generated file is the heroicons package, which reads the icon
+// bodies from the embedded dataset cache.
+func (cfg config) builtin() bool {
+	return cfg.set.prefix == heroiconsPrefix && cfg.pkg == packageName
+}
+
+// outputFilePath returns the path of the generated Go file.
+func (cfg config) outputFilePath() string {
+	return filepath.Join(cfg.outputDir, fmt.Sprintf(outputFile, cfg.set.prefix))
+}
+
+// parseFlags parses the command-line arguments into a config.
+// Paths default to the repository layout, relative to the working directory.
+// Other icon sets than heroicons default to a package named after their prefix.
+func parseFlags(args []string) (config, error) {
+	cfg, _, err := parseFlagSet("icons-maker", args, nil)
+	return cfg, err
+}
+
+// parseFlagSet parses the arguments of a command, with the extra flags it registers,
+// and returns the config with the names of the flags set.
+func parseFlagSet(name string, args []string, extra func(*flag.FlagSet)) (config, map[string]bool, error) {
+	var cfg config
+	var prefix, variants string
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.StringVar(&prefix, "prefix", heroiconsPrefix, "Iconify prefix of the icon set (e.g., lucide, tabler, mdi)")
+	fs.StringVar(&variants, "variants", "", "variant rules as comma-separated suffix=Type[:NameSuffix] (e.g., \"=Outline,filled=Solid\")")
+	fs.StringVar(&cfg.input, "input", fmt.Sprintf(datasetURL, heroiconsPrefix), "dataset URL or local Iconify JSON file (default the Iconify dataset of the prefix)")
+	fs.StringVar(&cfg.cachePath, "cache", filepath.Join("data", fmt.Sprintf(cacheFile, heroiconsPrefix)), "cache file of the dataset downloaded from a URL")
+	fs.StringVar(&cfg.outputDir, "out", ".", "output directory of the generated file (default a directory named after the package for other icon sets)")
+	fs.StringVar(&cfg.pkg, "pkg", packageName, "package name of the generated file (default derived from the prefix for other icon sets)")
+	fs.DurationVar(&cfg.cacheTTL, "cache-ttl", cacheDuration, "maximum age of the cache before fetching the dataset again")
+	fs.BoolVar(&cfg.offline, "offline", false, "never fetch the dataset, use the cache regardless of its age")
+	fs.BoolVar(&cfg.forceFetch, "force-fetch", false, "fetch the dataset even if the cache is fresh")
+	fs.BoolVar(&cfg.check, "check", false, "verify that the generated file, the manifest (and the cache of a local heroicons -input) match the dataset, without fetching or writing")
+	fs.StringVar(&cfg.report, "report", "", "write a markdown report of the dataset changes (e.g., for a pull request), \"-\" for stdout")
+	fs.StringVar(&cfg.changie, "changie", "", "write the dataset changes as changie entries in a directory (e.g., .changes/unreleased)")
+	fs.StringVar(&cfg.manifest, "manifest", filepath.Join("data", fmt.Sprintf(manifestFile, heroiconsPrefix)), "manifest of the generated icons, keeping removed icons as deprecated shims (empty to disable)")
+	fs.StringVar(&cfg.compare, "compare", "", "previous dataset of the report and the deprecated shims (default the cache before fetching)")
+	if extra != nil {
+		extra(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return config{}, nil, err
+	}
+
+	set, err := newIconSet(prefix, variants)
+	if err != nil {
+		return config{}, nil, err
+	}
+	cfg.set = set
+	setFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { setFlags[f.Name] = true })
+	if prefix != heroiconsPrefix {
+		applyPrefixDefaults(&cfg, setFlags)
+	}
+
+	switch {
+	case prefix == "" || packageNameOf(prefix) == "":
+		return config{}, nil, fmt.Errorf("invalid prefix %q", prefix)
+	case fs.NArg() > 0:
+		return config{}, nil, fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	case cfg.offline && cfg.forceFetch:
+		return config{}, nil, fmt.Errorf("-offline and -force-fetch are mutually exclusive")
+	case cfg.check && cfg.forceFetch:
+		return config{}, nil, fmt.Errorf("-check and -force-fetch are mutually exclusive")
+	case cfg.check && cfg.reports():
+		return config{}, nil, fmt.Errorf("-check cannot write -report or -changie")
+	case cfg.compare != "" && !cfg.reports() && cfg.manifest == "":
+		return config{}, nil, fmt.Errorf("-compare requires -report, -changie or -manifest")
+	case cfg.input == "":
+		return config{}, nil, fmt.Errorf("-input is required")
+	case !token.IsIdentifier(cfg.pkg):
+		return config{}, nil, fmt.Errorf("invalid package name %q", cfg.pkg)
+	case prefix != heroiconsPrefix && cfg.pkg == packageName:
+		return config{}, nil, fmt.Errorf("package name %q is reserved for heroicons", cfg.pkg)
+	case cfg.cacheTTL < 0:
+		return config{}, nil, fmt.Errorf("invalid cache TTL %s", cfg.cacheTTL)
+	}
+	return cfg, setFlags, nil
+}
+
+// applyPrefixDefaults derives the defaults of the flags left unset from the prefix of another icon set.
+func applyPrefixDefaults(cfg *config, setFlags map[string]bool) {
+	prefix := cfg.set.prefix
+	if !setFlags["input"] {
+		cfg.input = fmt.Sprintf(datasetURL, prefix)
+	}
+	if !setFlags["cache"] {
+		cfg.cachePath = filepath.Join("data", fmt.Sprintf(cacheFile, prefix))
+	}
+	if !setFlags["pkg"] {
+		cfg.pkg = packageNameOf(prefix)
+	}
+	if !setFlags["out"] {
+		cfg.outputDir = cfg.pkg
+	}
+	if !setFlags["manifest"] {
+		cfg.manifest = filepath.Join(cfg.outputDir, fmt.Sprintf(manifestFile, prefix))
+	}
+}
+
+// isURL reports whether the dataset input is a URL rather than a local file.
+func isURL(input string) bool {
+	return strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://")
+}
+
+// directionalIcons lists the icons (by base name) pointing in the reading direction,
+// which are mirrored in right-to-left layouts. Media controls (e.g., forward, backward)
+// keep the direction of the playback and are not listed.
+var directionalIcons = map[string]struct{}{
+	"arrow-down-left":                {},
+	"arrow-down-right":               {},
+	"arrow-left":                     {},
+	"arrow-left-circle":              {},
+	"arrow-left-end-on-rectangle":    {},
+	"arrow-left-on-rectangle":        {},
+	"arrow-left-start-on-rectangle":  {},
+	"arrow-long-left":                {},
+	"arrow-long-right":               {},
+	"arrow-right":                    {},
+	"arrow-right-circle":             {},
+	"arrow-right-end-on-rectangle":   {},
+	"arrow-right-on-rectangle":       {},
+	"arrow-right-start-on-rectangle": {},
+	"arrow-small-left":               {},
+	"arrow-small-right":              {},
+	"arrow-top-right-on-square":      {},
+	"arrow-turn-down-left":           {},
+	"arrow-turn-down-right":          {},
+	"arrow-turn-left-down":           {},
+	"arrow-turn-left-up":             {},
+	"arrow-turn-right-down":          {},
+	"arrow-turn-right-up":            {},
+	"arrow-turn-up-left":             {},
+	"arrow-turn-up-right":            {},
+	"arrow-up-left":                  {},
+	"arrow-up-right":                 {},
+	"arrow-uturn-left":               {},
+	"arrow-uturn-right":              {},
+	"backspace":                      {},
+	"bars-3-bottom-left":             {},
+	"bars-3-bottom-right":            {},
+	"bars-3-center-left":             {},
+	"chevron-double-left":            {},
+	"chevron-double-right":           {},
+	"chevron-left":                   {},
+	"chevron-right":                  {},
+	"paper-airplane":                 {},
+}
+
 // Utility for consistent error logging
 func logAndExit(err error, context string) {
 	log.Fatalf("%s: %v", context, err)
@@ -69,7 +250,7 @@ func saveCache(filepath string, data []byte) error {
 func fetchDatasetWithRetry(url string, maxRetries int, delay time.Duration) ([]byte, error) {
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		log.Printf("Fetching heroicons dataset (attempt %d/%d)...\n", attempt, maxRetries)
+		log.Printf("Fetching dataset (attempt %d/%d)...\n", attempt, maxRetries)
 		resp, err := http.Get(url)
 		if err != nil || resp.StatusCode != http.StatusOK {
 			if resp != nil {
@@ -107,88 +288,130 @@ func fetchAndCacheDataset(url string, cachePath string, maxAge time.Duration) ([
 	return data, nil
 }
 
-// Parses icons from the JSON dataset using gjson.
-func parseIcons(jsonData []byte) (map[string]*heroicons.Icon, error) {
+// generatedIcon is an icon definition with its generated variable name.
+type generatedIcon struct {
+	icon       *heroicons.Icon
+	structName string
+	baseName   string // Name without the variant suffix (e.g., "moon")
+	body       string // SVG body, for the preview
+	width      int    // Width of the viewBox
+	height     int    // Height of the viewBox
+}
+
+// iconDimension returns the width or height of an icon, defaulting to the dataset one.
+func iconDimension(icon gjson.Result, jsonData []byte, key string) int {
+	if value := icon.Get(key); value.Exists() {
+		return int(value.Int())
+	}
+	if value := gjson.GetBytes(jsonData, key); value.Exists() {
+		return int(value.Int())
+	}
+	return 16 // Iconify default
+}
+
+// Parses icons from the JSON dataset using gjson. Icons with a non-square viewBox are skipped with a warning.
+func parseIcons(jsonData []byte, set *iconSet) (map[string]*generatedIcon, error) {
 	result := gjson.GetBytes(jsonData, "icons")
 
 	if !result.Exists() {
-		return nil, fmt.Errorf("no icons found in JSON data")
+		return nil, errNoIcons
+	}
+	if prefix := gjson.GetBytes(jsonData, "prefix").String(); prefix != "" && prefix != set.prefix {
+		return nil, fmt.Errorf("dataset prefix %q does not match %q", prefix, set.prefix)
 	}
 
-	icons := make(map[string]*heroicons.Icon)
+	variants, err := parseVariants(jsonData, set.variants)
+	if err != nil {
+		return nil, err
+	}
+
+	icons := make(map[string]*generatedIcon)
+	structNames := make(map[string]string)
 
 	result.ForEach(func(key, value gjson.Result) bool {
 		name := key.String()
 
-		icon := &heroicons.Icon{
-			Name: name,
-			Size: Size24, // Default size
-			Type: "Outline",
+		var baseName string
+		var variant variantRule
+		baseName, variant, err = classifyIcon(name, variants)
+		if err != nil {
+			return false
 		}
 
-		if strings.Contains(name, "solid") {
-			icon.Type = "Solid"
+		width := iconDimension(value, jsonData, "width")
+		height := iconDimension(value, jsonData, "height")
+		if width != height {
+			// Icons render in a square viewBox: skip the icon rather than the whole set
+			log.Printf("Warning: skipping icon %q with a non-square viewBox (%dx%d)\n", name, width, height)
+			return true
 		}
 
-		switch {
-		case strings.Contains(name, "16"):
-			icon.Size = Size16
-			icon.Type = "Micro"
-		case strings.Contains(name, "20"):
-			icon.Size = Size20
-			icon.Type = "Mini"
+		icon := &heroicons.Icon{
+			Name: name,
+			Type: variant.iconType,
+			Size: variant.size,
+		}
+		if icon.Size == "" {
+			icon.Size = heroicons.Size(strconv.Itoa(width))
 		}
+		_, icon.Directional = set.directional[baseName]
 
-		icons[name] = icon
+		structName := toPascalCase(baseName) + variant.nameSuffix
+		if structName != "" && unicode.IsDigit(rune(structName[0])) {
+			structName = "Icon" + structName // Identifiers cannot start with a digit (e.g., "2fa")
+		}
+		if !token.IsIdentifier(structName) {
+			err = fmt.Errorf("icon %q generates the invalid identifier %q", name, structName)
+			return false
+		}
+		if other, found := structNames[structName]; found {
+			err = fmt.Errorf("icons %q and %q both generate %s", other, name, structName)
+			return false
+		}
+		structNames[structName] = name
+
+		icons[name] = &generatedIcon{
+			icon:       icon,
+			structName: structName,
+			baseName:   baseName,
+			body:       value.Get("body").String(),
+			width:      width,
+			height:     height,
+		}
 		return true
 	})
+	if err != nil {
+		return nil, err
+	}
 
 	return icons, nil
 }
 
-// Cleans and standardizes icon names.
-func cleanIconName(name string) string {
-	return strings.NewReplacer("-16", "", "-20", "", "-solid", "").Replace(name)
-}
-
-// Generates the Go struct name for an icon.
-func generateStructName(icon *heroicons.Icon) string {
-	baseName := toPascalCase(cleanIconName(icon.Name))
-	switch icon.Type {
-	case "Micro":
-		return baseName + "Micro"
-	case "Mini":
-		return baseName + "Mini"
-	case "Solid":
-		return baseName + "Solid"
-	default:
-		return baseName
+// checkGoFile compares the Go file on disk with the regenerated source,
+// and returns errOutdated with a diff when they differ.
+func checkGoFile(outputFilePath string, generated []byte) error {
+	current, err := os.ReadFile(outputFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	diff := unifiedDiff(outputFilePath, outputFilePath+" (regenerated)", string(current), string(generated))
+	if diff == "" {
+		return nil
 	}
+	return fmt.Errorf("%w\n%s", errOutdated, diff)
 }
 
-// Generates a Go file with icon definitions.
-func generateGoFile(outputFilePath string, icons map[string]*heroicons.Icon) error {
-	outFile, err := os.Create(outputFilePath)
-	if err != nil {
+// checkCache returns errOutdated when the dataset cache embedded by the heroicons package
+// differs from the local dataset.
+func checkCache(cachePath string, data []byte) error {
+	cached, err := loadCache(cachePath)
+	if err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	defer outFile.Close()
-
-	var builder strings.Builder
-	builder.WriteString("// Code generated by 'scripts/icons-maker.go'; DO NOT EDIT.\n")
-	builder.WriteString("package templheroicons\n\nvar (\n")
-	var structs []string
-	for _, icon := range icons {
-		structs = append(structs, fmt.Sprintf("\t%s = &Icon{Name: \"%s\", Type: \"%s\", Size: \"%s\"}\n",
-			generateStructName(icon), icon.Name, icon.Type, icon.Size.String()))
-	}
-	sort.Strings(structs)
-	for _, structDef := range structs {
-		builder.WriteString(structDef)
+	if !bytes.Equal(cached, data) {
+		return fmt.Errorf("%w: %s does not match the -input dataset", errOutdated, cachePath)
 	}
-	builder.WriteString(")\n")
-	_, err = outFile.WriteString(builder.String())
-	return err
+	return nil
 }
 
 // ensureDir ensures that the specified directory exists. If it does not exist, it creates it.
@@ -200,52 +423,231 @@ func ensureDir(dir string) error {
 	return nil
 }
 
-func main() {
-	cacheFilePath := path.Join("..", "data", cacheFile)
-	outputFilePath := path.Join("..", outputFile)
+// loadDataset reads the dataset from a local file, or from the cache of a URL,
+// fetching it again when the cache is stale or a fetch is forced. A local dataset of
+// the heroicons package is copied to the cache, which the package embeds.
+func loadDataset(cfg config) ([]byte, error) {
+	if !isURL(cfg.input) {
+		data, err := os.ReadFile(cfg.input)
+		if err != nil || !cfg.builtin() || cfg.check {
+			return data, err
+		}
+		if err := ensureDir(filepath.Dir(cfg.cachePath)); err != nil {
+			return nil, err
+		}
+		if err := saveCache(cfg.cachePath, data); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	if cfg.offline || cfg.check {
+		log.Println("Offline mode: using cached dataset...")
+		return loadCache(cfg.cachePath)
+	}
+
+	maxAge := cfg.cacheTTL
+	if cfg.forceFetch {
+		maxAge = 0
+	}
+	// Ensure the cache directory exists.
+	if err := ensureDir(filepath.Dir(cfg.cachePath)); err != nil {
+		return nil, err
+	}
+	return fetchAndCacheDataset(cfg.input, cfg.cachePath, maxAge)
+}
 
-	// Ensure the "data" directory exists.
-	dataDir := path.Dir(cacheFilePath)
-	if err := ensureDir(dataDir); err != nil {
-		log.Fatalf("Error ensuring data directory exists: %v", err)
+// loadIcons loads and parses the dataset, fetching it again once if a cached dataset has no icons.
+func loadIcons(cfg config) ([]byte, *datasetInfo, error) {
+	data, err := loadDataset(cfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading dataset: %w", err)
 	}
 
-	var data []byte
-	var icons map[string]*heroicons.Icon
-	var err error
+	info, err := parseDatasetInfo(data, cfg.set)
+	if errors.Is(err, errNoIcons) && isURL(cfg.input) && !cfg.offline && !cfg.check && !cfg.forceFetch {
+		// The cached dataset may be truncated: fetch it again once.
+		log.Println("No icons found in JSON data. Forcing dataset re-fetch...")
+		cfg.forceFetch = true
+		return loadIcons(cfg)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing icons: %w", err)
+	}
+	return data, info, nil
+}
 
-	// Attempt to fetch and parse the JSON dataset.
-	forceFetch := false
-	for {
-		data, err = fetchAndCacheDataset(datasetURL, cacheFilePath, cacheDuration)
-		if err != nil {
-			logAndExit(err, "Fetching dataset")
+// loadPreviousDataset reads the dataset to compare with for the report and the artwork of
+// the shims: the -compare file, or the cache as it is before fetching or copying the new
+// dataset. It returns nil without a previous dataset (a local input of another package,
+// or no cache yet).
+func loadPreviousDataset(cfg config) ([]byte, error) {
+	if cfg.compare != "" {
+		return os.ReadFile(cfg.compare)
+	}
+	if !isURL(cfg.input) && !cfg.builtin() {
+		return nil, nil
+	}
+	data, err := loadCache(cfg.cachePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return data, err
+}
+
+// writeReport writes the markdown report of the dataset changes, to stdout for "-",
+// and the changie entries of the changes.
+func writeReport(cfg config, previous, data []byte) error {
+	report, err := compareDatasets(previous, data, cfg.set)
+	if err != nil {
+		return err
+	}
+	if report.isEmpty() {
+		log.Println("No icon changes in the dataset, skipping the report.")
+		return nil
+	}
+	if cfg.changie != "" {
+		if err := writeChangieFragments(cfg, report, time.Now()); err != nil {
+			return err
 		}
+	}
+	switch cfg.report {
+	case "":
+		return nil
+	case "-":
+		_, err := os.Stdout.WriteString(report.markdown(cfg.manifest != ""))
+		return err
+	}
+	if err := ensureDir(filepath.Dir(cfg.report)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(cfg.report, []byte(report.markdown(cfg.manifest != "")), 0644); err != nil {
+		return err
+	}
+	log.Printf("%s successfully created.\n", cfg.report)
+	return nil
+}
+
+// writeChangieFragments writes one changie entry per kind of change in the changie directory.
+func writeChangieFragments(cfg config, report *datasetReport, now time.Time) error {
+	if err := ensureDir(cfg.changie); err != nil {
+		return err
+	}
+	for _, fragment := range report.changieFragments(cfg.manifest != "", now) {
+		path := filepath.Join(cfg.changie, fragment.name)
+		if err := os.WriteFile(path, []byte(fragment.content), 0644); err != nil {
+			return err
+		}
+		log.Printf("%s successfully created.\n", path)
+	}
+	return nil
+}
 
-		icons, err = parseIcons(data)
-		if err == nil {
-			break
+// run loads and parses the dataset, then generates the Go file with icon definitions,
+// or compares it with the file on disk in check mode.
+func run(cfg config) error {
+	var previous []byte
+	if cfg.reports() || cfg.manifest != "" {
+		var err error
+		if previous, err = loadPreviousDataset(cfg); err != nil {
+			return fmt.Errorf("loading previous dataset: %w", err)
 		}
+	}
+	if previous == nil && cfg.reports() {
+		return fmt.Errorf("loading previous dataset: -report and -changie require -compare or a cached dataset")
+	}
 
-		if strings.Contains(err.Error(), "no icons found in JSON data") {
-			if forceFetch {
-				logAndExit(err, "Forced fetch failed to resolve the issue")
+	data, info, err := loadIcons(cfg)
+	if err != nil {
+		return err
+	}
+
+	// Keep the icons generated so far and removed from the dataset as deprecated shims
+	var shims []*deprecatedIcon
+	if cfg.manifest != "" {
+		previousManifest, err := loadManifest(cfg.manifest)
+		if err != nil {
+			return fmt.Errorf("loading manifest: %w", err)
+		}
+		var previousInfo *datasetInfo
+		if previous != nil {
+			if previousInfo, err = parseDatasetInfo(previous, cfg.set); err != nil {
+				return fmt.Errorf("parsing previous dataset: %w", err)
 			}
-			log.Println("No icons found in JSON data. Forcing dataset re-fetch...")
-			forceFetch = true
+		}
+		if shims, err = deprecatedIcons(previousManifest, info, previousInfo); err != nil {
+			return fmt.Errorf("generating shims: %w", err)
+		}
+	}
+
+	if cfg.reports() {
+		if err := writeReport(cfg, previous, data); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	}
 
-			if err := os.Remove(cacheFilePath); err != nil && !os.IsNotExist(err) {
-				logAndExit(err, "Failed to remove cache file")
+	outputFilePath := cfg.outputFilePath()
+	if cfg.check {
+		generated, err := renderGoFile(cfg, info.icons, shims)
+		if err != nil {
+			return fmt.Errorf("generating Go file: %w", err)
+		}
+		if err := checkGoFile(outputFilePath, generated); err != nil {
+			return err
+		}
+		if cfg.builtin() && !isURL(cfg.input) {
+			if err := checkCache(cfg.cachePath, data); err != nil {
+				return err
 			}
-		} else {
-			logAndExit(err, "Parsing icons")
 		}
+		if cfg.manifest != "" {
+			if err := checkManifest(cfg.manifest, newManifest(info, shims)); err != nil {
+				return err
+			}
+		}
+		log.Printf("%s is up to date.\n", outputFilePath)
+		return nil
+	}
+
+	if err := ensureDir(cfg.outputDir); err != nil {
+		return err
+	}
+	if err := generateGoFile(cfg, info.icons, shims); err != nil {
+		return fmt.Errorf("generating Go file: %w", err)
+	}
+	if cfg.manifest != "" {
+		if err := saveManifest(cfg.manifest, newManifest(info, shims)); err != nil {
+			return fmt.Errorf("saving manifest: %w", err)
+		}
+	}
+
+	log.Printf("%s successfully created.\n", outputFilePath)
+	return nil
+}
+
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		cfg, dir, err := parseImportFlags(os.Args[2:])
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		if err != nil {
+			logAndExit(err, "Parsing flags")
+		}
+		if err := runImport(cfg, dir); err != nil {
+			logAndExit(err, "Importing icons")
+		}
+		return
 	}
 
-	// Generate Go file with icon definitions.
-	if err := generateGoFile(outputFilePath, icons); err != nil {
-		logAndExit(err, "Generating Go file")
+	cfg, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logAndExit(err, "Parsing flags")
 	}
 
-	log.Println("heroicons_generated.go successfully created.")
+	if err := run(cfg); err != nil {
+		logAndExit(err, "Generating icons")
+	}
 }
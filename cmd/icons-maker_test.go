@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFlags(t *testing.T) {
+	cfg, err := parseFlags(nil)
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	expected := config{
+		input:     "https://raw.githubusercontent.com/iconify/icon-sets/refs/heads/master/json/heroicons.json",
+		cachePath: filepath.Join("data", "heroicons_cache.json"),
+		outputDir: ".",
+		pkg:       packageName,
+		cacheTTL:  cacheDuration,
+		manifest:  filepath.Join("data", "heroicons_manifest.json"),
+		set:       heroiconsSet,
+	}
+	if cfg != expected {
+		t.Errorf("parseFlags() = %+v, want %+v", cfg, expected)
+	}
+
+	cfg, err = parseFlags([]string{"-input", "icons.json", "-out", "gen", "-pkg", "icons", "-cache-ttl", "1h", "-offline"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if cfg.input != "icons.json" || cfg.outputDir != "gen" || cfg.pkg != "icons" || cfg.cacheTTL != time.Hour || !cfg.offline {
+		t.Errorf("parseFlags() = %+v", cfg)
+	}
+
+	// Other icon sets default to a package named after their prefix
+	cfg, err = parseFlags([]string{"-prefix", "material-symbols", "-variants", "=Outline,rounded=Solid"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	expected = config{
+		input:     "https://raw.githubusercontent.com/iconify/icon-sets/refs/heads/master/json/material-symbols.json",
+		cachePath: filepath.Join("data", "material-symbols_cache.json"),
+		outputDir: "materialsymbols",
+		pkg:       "materialsymbols",
+		cacheTTL:  cacheDuration,
+		manifest:  filepath.Join("materialsymbols", "material-symbols_manifest.json"),
+		set:       cfg.set,
+	}
+	if cfg != expected {
+		t.Errorf("parseFlags() = %+v, want %+v", cfg, expected)
+	}
+	if cfg.set.prefix != "material-symbols" || len(cfg.set.variants) != 2 || cfg.set.variants["rounded"].nameSuffix != "Rounded" {
+		t.Errorf("parseFlags() icon set = %+v", cfg.set)
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"Offline and force fetch", []string{"-offline", "-force-fetch"}, "-offline and -force-fetch are mutually exclusive"},
+		{"Check and force fetch", []string{"-check", "-force-fetch"}, "-check and -force-fetch are mutually exclusive"},
+		{"Check with report", []string{"-check", "-report", "-"}, "-check cannot write -report or -changie"},
+		{"Check with changie", []string{"-check", "-changie", ".changes/unreleased"}, "-check cannot write -report or -changie"},
+		{"Compare without report", []string{"-compare", "old.json", "-manifest", ""}, "-compare requires -report, -changie or -manifest"},
+		{"Invalid package name", []string{"-pkg", "my-icons"}, `invalid package name "my-icons"`},
+		{"Empty input", []string{"-input", ""}, "-input is required"},
+		{"Negative cache TTL", []string{"-cache-ttl", "-1h"}, "invalid cache TTL -1h0m0s"},
+		{"Unexpected argument", []string{"extra"}, "unexpected arguments: extra"},
+		{"Invalid prefix", []string{"-prefix", "--"}, `invalid prefix "--"`},
+		{"Reserved package name", []string{"-prefix", "lucide", "-pkg", "templheroicons"}, `package name "templheroicons" is reserved for heroicons`},
+		{"Invalid variant rule", []string{"-variants", "solid"}, `invalid variant rule "solid": expected suffix=Type[:NameSuffix]`},
+		{"Unknown icon type", []string{"-variants", "=Outline,filled=Filled"}, `invalid variant rule "filled=Filled": unknown icon type "Filled"`},
+		{"Duplicate suffix", []string{"-variants", "=Outline,=Solid"}, `invalid variant rule "=Solid": duplicate suffix ""`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseFlags(tt.args)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("parseFlags(%q) error = %v, want %q", tt.args, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRunLocalInput(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "icons.json")
+	dataset := `{"prefix":"heroicons","icons":{"moon":{"body":"<path/>"},"moon-solid":{"body":"<path/>"}},"suffixes":{"":"Outline 24x24","solid":"Solid 24x24"}}`
+	if err := os.WriteFile(input, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cachePath := filepath.Join(dir, "data", "heroicons_cache.json")
+	cfg := config{input: input, cachePath: cachePath, outputDir: filepath.Join(dir, "out"), pkg: packageName, set: heroiconsSet}
+	if err := run(cfg); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	// The heroicons package embeds the cache: a local dataset replaces it
+	if cached, err := os.ReadFile(cachePath); err != nil || string(cached) != dataset {
+		t.Errorf("cache = %q, %v, want the local dataset", cached, err)
+	}
+
+	generated, err := os.ReadFile(filepath.Join(dir, "out", "heroicons_generated.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{"package templheroicons\n", `Moon = &Icon{Name: "moon"`, `MoonSolid = &Icon{Name: "moon-solid"`} {
+		if !strings.Contains(string(generated), expected) {
+			t.Errorf("generated file = %q, want %q", generated, expected)
+		}
+	}
+}
+
+func TestRunOfflineWithoutCache(t *testing.T) {
+	cfg := config{input: fmt.Sprintf(datasetURL, heroiconsPrefix), cachePath: filepath.Join(t.TempDir(), "heroicons_cache.json"), offline: true, set: heroiconsSet}
+	if err := run(cfg); err == nil || !strings.HasPrefix(err.Error(), "loading dataset: ") {
+		t.Errorf("run() error = %v, want a loading error", err)
+	}
+}
+
+// heroiconsSuffixes is the suffixes table of the heroicons dataset.
+const heroiconsSuffixes = `"suffixes":{"":"Outline 24x24","solid":"Solid 24x24","20-solid":"Solid 20x20","16-solid":"Solid 16x16"}`
+
+func TestParseIcons(t *testing.T) {
+	dataset := `{"icons":{
+		"moon":{}, "moon-solid":{}, "moon-20-solid":{}, "moon-16-solid":{},
+		"arrow-left":{}, "arrow-left-20-solid":{},
+		"solid-state":{}, "circle-16":{}, "rectangle-20-20":{}, "solidify-solid":{}, "battery-20-solid-16-solid":{}
+	},` + heroiconsSuffixes + `}`
+
+	icons, err := parseIcons([]byte(dataset), heroiconsSet)
+	if err != nil {
+		t.Fatalf("parseIcons() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		structName  string
+		iconType    string
+		size        string
+		directional bool
+	}{
+		{"moon", "Moon", "Outline", "24", false},
+		{"moon-solid", "MoonSolid", "Solid", "24", false},
+		{"moon-20-solid", "MoonMini", "Mini", "20", false},
+		{"moon-16-solid", "MoonMicro", "Micro", "16", false},
+		{"arrow-left", "ArrowLeft", "Outline", "24", true},
+		{"arrow-left-20-solid", "ArrowLeftMini", "Mini", "20", true},
+		// Base names containing "solid", "16" or "20" are not variants
+		{"solid-state", "SolidState", "Outline", "24", false},
+		{"circle-16", "Circle16", "Outline", "24", false},
+		{"rectangle-20-20", "Rectangle2020", "Outline", "24", false},
+		{"solidify-solid", "SolidifySolid", "Solid", "24", false},
+		{"battery-20-solid-16-solid", "Battery20SolidMicro", "Micro", "16", false},
+	}
+
+	if len(icons) != len(tests) {
+		t.Errorf("parseIcons() returned %d icons, want %d", len(icons), len(tests))
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			generated, ok := icons[tt.name]
+			if !ok {
+				t.Fatalf("parseIcons() is missing %q", tt.name)
+			}
+			icon := generated.icon
+			if generated.structName != tt.structName || icon.Type != tt.iconType || icon.Size.String() != tt.size || icon.Directional != tt.directional {
+				t.Errorf("parseIcons()[%q] = %s %s %s directional=%v, want %s %s %s directional=%v", tt.name,
+					generated.structName, icon.Type, icon.Size, icon.Directional,
+					tt.structName, tt.iconType, tt.size, tt.directional)
+			}
+		})
+	}
+}
+
+func TestParseIconsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		dataset  string
+		expected string
+	}{
+		{
+			name:     "No icons",
+			dataset:  `{"prefix":"heroicons"}`,
+			expected: "no icons found in JSON data",
+		},
+		{
+			name:     "Other icon set",
+			dataset:  `{"prefix":"lucide","icons":{"moon":{}}}`,
+			expected: `dataset prefix "lucide" does not match "heroicons"`,
+		},
+		{
+			name:     "Unknown suffix",
+			dataset:  `{"icons":{"moon":{}},"suffixes":{"":"Outline 24x24","32-solid":"Solid 32x32"}}`,
+			expected: `unknown variant suffix "32-solid" (Solid 32x32)`,
+		},
+		{
+			name:     "Icon without a matching suffix",
+			dataset:  `{"icons":{"moon-solid":{},"moon":{}},"suffixes":{"solid":"Solid 24x24"}}`,
+			expected: `icon "moon" matches no variant suffix`,
+		},
+		{
+			name:     "Suffix without base name",
+			dataset:  `{"icons":{"-solid":{}},"suffixes":{"solid":"Solid 24x24"}}`,
+			expected: `icon "-solid" matches no variant suffix`,
+		},
+		{
+			name:     "Conflicting variable names",
+			dataset:  `{"icons":{"arrow-up":{},"arrow--up":{}},` + heroiconsSuffixes + `}`,
+			expected: `icons "arrow-up" and "arrow--up" both generate ArrowUp`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseIcons([]byte(tt.dataset), heroiconsSet)
+			if err == nil || err.Error() != tt.expected {
+				t.Errorf("parseIcons() error = %v, want %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseIconsNonSquare(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	icons, err := parseIcons([]byte(`{"icons":{"moon":{"width":20},"sun":{}},`+heroiconsSuffixes+`}`), heroiconsSet)
+	if err != nil {
+		t.Fatalf("parseIcons() error = %v", err)
+	}
+	if _, found := icons["moon"]; found || len(icons) != 1 {
+		t.Errorf("parseIcons() = %v, want only the square icon", icons)
+	}
+	if expected := `Warning: skipping icon "moon" with a non-square viewBox (20x16)`; !strings.Contains(logs.String(), expected) {
+		t.Errorf("logs = %q, want %q", logs.String(), expected)
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "icons.json")
+	dataset := `{"icons":{"moon":{"body":"<path/>"},"sun":{"body":"<path/>"}},` + heroiconsSuffixes + `}`
+	if err := os.WriteFile(input, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config{input: input, cachePath: filepath.Join(dir, "heroicons_cache.json"), outputDir: dir, pkg: packageName, set: heroiconsSet}
+	if err := run(cfg); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	cfg.check = true
+	if err := run(cfg); err != nil {
+		t.Errorf("run() in check mode error = %v, want the file up to date", err)
+	}
+
+	// The embedded cache must match the local dataset
+	if err := os.WriteFile(cfg.cachePath, []byte(`{}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(cfg); !errors.Is(err, errOutdated) || !strings.Contains(err.Error(), "heroicons_cache.json does not match the -input dataset") {
+		t.Errorf("run() in check mode error = %v, want an outdated cache", err)
+	}
+
+	// Edit the dataset without regenerating
+	dataset = `{"icons":{"moon":{"body":"<path/>"},"star":{"body":"<path/>"}},` + heroiconsSuffixes + `}`
+	if err := os.WriteFile(input, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+	err := run(cfg)
+	if !errors.Is(err, errOutdated) {
+		t.Fatalf("run() in check mode error = %v, want %v", err, errOutdated)
+	}
+	for _, expected := range []string{
+		"\n-\tSun = &Icon{Name: \"sun\", Type: \"Outline\", Size: \"24\"}\n",
+		"\n+\tStar = &Icon{Name: \"star\", Type: \"Outline\", Size: \"24\"}\n",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("run() in check mode error = %q, want diff line %q", err, expected)
+		}
+	}
+
+	// The generated file is left untouched
+	generated, err := os.ReadFile(cfg.outputFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(generated), "Star") {
+		t.Errorf("run() in check mode wrote the generated file")
+	}
+}
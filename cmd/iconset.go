@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	heroicons "github.com/indaco/templheroicons"
+	"github.com/tidwall/gjson"
+)
+
+// heroiconsPrefix is the Iconify prefix of the heroicons dataset.
+const heroiconsPrefix = "heroicons"
+
+// iconSet describes how to generate the icons of an Iconify dataset.
+type iconSet struct {
+	prefix      string                 // Iconify prefix (e.g., "heroicons", "lucide")
+	variants    map[string]variantRule // Variant rules, by suffix
+	directional map[string]struct{}    // Base names of the icons mirrored in RTL layouts
+}
+
+// heroiconsSet is the icon set of the heroicons dataset.
+var heroiconsSet = &iconSet{
+	prefix:      heroiconsPrefix,
+	variants:    heroiconsVariants,
+	directional: directionalIcons,
+}
+
+// newIconSet returns the icon set of a prefix, with variant rules parsed from the -variants
+// flag. Without rules, heroicons uses its own variants and other sets a single Outline variant.
+func newIconSet(prefix, rules string) (*iconSet, error) {
+	if prefix == heroiconsPrefix && rules == "" {
+		return heroiconsSet, nil
+	}
+	if rules == "" {
+		rules = "=Outline"
+	}
+	variants, err := parseVariantRules(rules)
+	if err != nil {
+		return nil, err
+	}
+	set := &iconSet{prefix: prefix, variants: variants}
+	if prefix == heroiconsPrefix {
+		set.directional = directionalIcons
+	}
+	return set, nil
+}
+
+// variantRule maps a dataset suffix (e.g., "20-solid") to an icon variant.
+type variantRule struct {
+	suffix     string         // Suffix of the icon names, without the leading dash
+	iconType   string         // Icon type (e.g., "Mini")
+	size       heroicons.Size // Default size of the variant, empty for the viewBox size
+	nameSuffix string         // Suffix of the generated variable names (e.g., "Mini")
+}
+
+// heroiconsVariants lists the variants of the heroicons dataset, by suffix.
+var heroiconsVariants = map[string]variantRule{
+	"":         {suffix: "", iconType: "Outline", size: heroicons.Size24, nameSuffix: ""},
+	"solid":    {suffix: "solid", iconType: "Solid", size: heroicons.Size24, nameSuffix: "Solid"},
+	"20-solid": {suffix: "20-solid", iconType: "Mini", size: heroicons.Size20, nameSuffix: "Mini"},
+	"16-solid": {suffix: "16-solid", iconType: "Micro", size: heroicons.Size16, nameSuffix: "Micro"},
+}
+
+// iconTypes lists the icon types supported by the rendering pipeline.
+var iconTypes = map[string]struct{}{"Outline": {}, "Solid": {}, "Mini": {}, "Micro": {}}
+
+// parseVariantRules parses comma-separated variant rules of the form `suffix=Type[:NameSuffix]`,
+// e.g. "=Outline,filled=Solid". The name suffix defaults to the suffix in PascalCase.
+func parseVariantRules(rules string) (map[string]variantRule, error) {
+	variants := make(map[string]variantRule)
+	for _, rule := range strings.Split(rules, ",") {
+		suffix, variant, ok := strings.Cut(strings.TrimSpace(rule), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid variant rule %q: expected suffix=Type[:NameSuffix]", rule)
+		}
+		iconType, nameSuffix, hasNameSuffix := strings.Cut(variant, ":")
+		if _, ok := iconTypes[iconType]; !ok {
+			return nil, fmt.Errorf("invalid variant rule %q: unknown icon type %q", rule, iconType)
+		}
+		if !hasNameSuffix {
+			nameSuffix = toPascalCase(suffix)
+		}
+		if _, found := variants[suffix]; found {
+			return nil, fmt.Errorf("invalid variant rule %q: duplicate suffix %q", rule, suffix)
+		}
+		variants[suffix] = variantRule{suffix: suffix, iconType: iconType, nameSuffix: nameSuffix}
+	}
+	return variants, nil
+}
+
+// parseVariants returns the variant rules of the suffixes declared by the dataset,
+// longest suffix first. It fails on suffixes without a known variant. Datasets without
+// a suffixes table use all the known variants.
+func parseVariants(jsonData []byte, known map[string]variantRule) ([]variantRule, error) {
+	var variants []variantRule
+	result := gjson.GetBytes(jsonData, "suffixes")
+	if result.IsObject() {
+		var err error
+		result.ForEach(func(key, value gjson.Result) bool {
+			rule, ok := known[key.String()]
+			if !ok {
+				err = fmt.Errorf("unknown variant suffix %q (%s)", key.String(), value.String())
+				return false
+			}
+			variants = append(variants, rule)
+			return true
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		for _, rule := range known {
+			variants = append(variants, rule)
+		}
+	}
+
+	// Match the longest suffix first, so "20-solid" wins over "solid"
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i].suffix) != len(variants[j].suffix) {
+			return len(variants[i].suffix) > len(variants[j].suffix)
+		}
+		return variants[i].suffix < variants[j].suffix
+	})
+	return variants, nil
+}
+
+// classifyIcon returns the base name and variant of an icon name, by exact suffix match.
+func classifyIcon(name string, variants []variantRule) (string, variantRule, error) {
+	for _, variant := range variants {
+		if variant.suffix == "" {
+			return name, variant, nil
+		}
+		if baseName, ok := strings.CutSuffix(name, "-"+variant.suffix); ok && baseName != "" {
+			return baseName, variant, nil
+		}
+	}
+	return "", variantRule{}, fmt.Errorf("icon %q matches no variant suffix", name)
+}
+
+// packageNameOf returns a Go package name derived from an Iconify prefix (e.g., "material-symbols").
+func packageNameOf(prefix string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(prefix) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9' && builder.Len() > 0) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackageNameOf(t *testing.T) {
+	tests := map[string]string{
+		"heroicons":        "heroicons",
+		"material-symbols": "materialsymbols",
+		"Lucide":           "lucide",
+		"42-icons":         "icons",
+		"--":               "",
+	}
+	for prefix, expected := range tests {
+		if got := packageNameOf(prefix); got != expected {
+			t.Errorf("packageNameOf(%q) = %q, want %q", prefix, got, expected)
+		}
+	}
+}
+
+func TestRunIconSets(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		variants string
+		expected []string
+	}{
+		{
+			prefix:   "lucide",
+			variants: "",
+			expected: []string{
+				"package lucide\n",
+				"import \"github.com/indaco/templheroicons\"\n",
+				"\t// Camera is the Outline variant of the \"camera\" icon, 24x24.\n",
+				"\tCamera = templheroicons.NewIcon(\"camera\", \"Outline\", 24, \"<g fill=\\\"none\\\"",
+				"\tAArrowDown = templheroicons.NewIcon(\"a-arrow-down\", \"Outline\", 24, ",
+				"\tIcon3dRotate = templheroicons.NewIcon(\"3d-rotate\", \"Outline\", 24, ",
+			},
+		},
+		{
+			prefix:   "tabler",
+			variants: "=Outline,filled=Solid",
+			expected: []string{
+				"package tabler\n",
+				"\tHeart = templheroicons.NewIcon(\"heart\", \"Outline\", 24, ",
+				"\t// HeartFilled is the Solid variant of the \"heart\" icon, 24x24.\n",
+				"\tHeartFilled = templheroicons.NewIcon(\"heart-filled\", \"Solid\", 24, ",
+				"\tStarFilled = templheroicons.NewIcon(\"star-filled\", \"Solid\", 20, ",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			dir := t.TempDir()
+			args := []string{"-prefix", tt.prefix, "-input", filepath.Join("testdata", tt.prefix+".json"), "-out", dir}
+			if tt.variants != "" {
+				args = append(args, "-variants", tt.variants)
+			}
+			cfg, err := parseFlags(args)
+			if err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+			if err := run(cfg); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+
+			path := filepath.Join(dir, tt.prefix+"_generated.go")
+			generated, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(string(generated), expected) {
+					t.Errorf("generated file = %q, want %q", generated, expected)
+				}
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), path, generated, parser.AllErrors); err != nil {
+				t.Errorf("generated file does not parse: %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.prefix+"_manifest.json")); err != nil {
+				t.Errorf("manifest not written: %v", err)
+			}
+		})
+	}
+}
+
+func TestRunIconSetsUnknownSuffix(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "tabler.json")
+	dataset := `{"prefix":"tabler","icons":{"heart":{}},"suffixes":{"":"Outline","filled":"Filled"}}`
+	if err := os.WriteFile(input, []byte(dataset), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := parseFlags([]string{"-prefix", "tabler", "-input", input, "-out", dir})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if err := run(cfg); err == nil || err.Error() != `parsing icons: unknown variant suffix "filled" (Filled)` {
+		t.Errorf("run() error = %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	heroicons "github.com/indaco/templheroicons"
+)
+
+// manifestFile is the default name of the manifest of generated icons, by prefix.
+const manifestFile = "%s_manifest.json"
+
+// manifest persists the icons generated so far, so that icons removed from the dataset
+// are kept as deprecated shims instead of breaking downstream builds. Only the shims
+// record their body: the artwork of the other icons is in the dataset.
+type manifest struct {
+	Version string                   `json:"version"` // Dataset version of the last generation
+	Icons   map[string]*manifestIcon `json:"icons"`   // Icons by variable name
+}
+
+// manifestIcon is an icon definition recorded in the manifest.
+type manifestIcon struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Size        string `json:"size"`
+	Directional bool   `json:"directional,omitempty"`
+	Width       int    `json:"width"`
+	Height      int    `json:"height"`
+	Body        string `json:"body,omitempty"`        // SVG body of a deprecated shim
+	RemovedIn   string `json:"removedIn,omitempty"`   // Dataset version removing the icon
+	Replacement string `json:"replacement,omitempty"` // Variable name of the icon replacing it
+}
+
+// deprecatedIcon is a shim generated for an icon removed from the dataset.
+type deprecatedIcon struct {
+	*generatedIcon
+	removedIn   string // Dataset version removing the icon
+	replacement string // Variable name of the icon replacing it, if renamed
+}
+
+// loadManifest reads the manifest, returning an empty one if the file does not exist.
+func loadManifest(path string) (*manifest, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &manifest{Icons: map[string]*manifestIcon{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var m manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing manifest %s: %w", path, err)
+	}
+	if m.Icons == nil {
+		m.Icons = map[string]*manifestIcon{}
+	}
+	return &m, nil
+}
+
+// encodeManifest encodes the manifest, with one field per line to keep diffs readable.
+func encodeManifest(m *manifest) ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// saveManifest writes the manifest.
+func saveManifest(path string, m *manifest) error {
+	data, err := encodeManifest(m)
+	if err != nil {
+		return err
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// checkManifest compares the manifest on disk with the regenerated one,
+// and returns errOutdated with a diff when they differ.
+func checkManifest(path string, m *manifest) error {
+	generated, err := encodeManifest(m)
+	if err != nil {
+		return err
+	}
+	current, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	diff := unifiedDiff(path, path+" (regenerated)", string(current), string(generated))
+	if diff == "" {
+		return nil
+	}
+	return fmt.Errorf("%w\n%s", errOutdated, diff)
+}
+
+// deprecatedIcons returns the shims of the manifest icons missing from the dataset.
+// Icons kept as aliases upstream point at their replacement; the others keep their
+// previous body until the next major version of the dataset. The body of an icon
+// removed since the last generation comes from the previous dataset, which may be nil
+// when no icon was removed.
+func deprecatedIcons(m *manifest, info, previous *datasetInfo) ([]*deprecatedIcon, error) {
+	structNames := make(map[string]*generatedIcon, len(info.icons))
+	for _, icon := range info.icons {
+		structNames[icon.structName] = icon
+	}
+
+	var shims []*deprecatedIcon
+	for structName, entry := range m.Icons {
+		if _, found := structNames[structName]; found {
+			continue
+		}
+		removedIn := entry.RemovedIn
+		if removedIn == "" {
+			removedIn = info.version
+		}
+		if isNewerMajor(info.version, removedIn) {
+			continue // The shim was kept for a whole major version
+		}
+		body := entry.Body
+		if body == "" {
+			var found bool
+			if body, found = previousBody(previous, entry.Name); !found {
+				return nil, fmt.Errorf("no previous artwork for the removed icon %s (%s), use -compare with the previous dataset", structName, entry.Name)
+			}
+		}
+
+		shim := &deprecatedIcon{
+			generatedIcon: &generatedIcon{
+				icon: &heroicons.Icon{
+					Name:        entry.Name,
+					Type:        entry.Type,
+					Size:        heroicons.Size(entry.Size),
+					Directional: entry.Directional,
+				},
+				structName: structName,
+				baseName:   entry.Name,
+				body:       body,
+				width:      entry.Width,
+				height:     entry.Height,
+			},
+			removedIn:   removedIn,
+			replacement: entry.Replacement,
+		}
+		if target, ok := info.resolveAlias(entry.Name); ok {
+			shim.replacement = target.structName
+		}
+		if _, found := structNames[shim.replacement]; !found {
+			shim.replacement = "" // The replacement was removed too
+		}
+		shims = append(shims, shim)
+	}
+	sort.Slice(shims, func(i, j int) bool {
+		return shims[i].structName < shims[j].structName
+	})
+	return shims, nil
+}
+
+// previousBody returns the body of an icon in the previous dataset.
+func previousBody(previous *datasetInfo, name string) (string, bool) {
+	if previous == nil {
+		return "", false
+	}
+	icon, found := previous.icons[name]
+	if !found {
+		return "", false
+	}
+	return icon.body, true
+}
+
+// isNewerMajor reports whether the major version of the dataset is above the one of since.
+// Unknown versions are never considered newer.
+func isNewerMajor(version, since string) bool {
+	var major, sinceMajor int
+	if _, err := fmt.Sscanf(strings.TrimPrefix(version, "v"), "%d", &major); err != nil {
+		return false
+	}
+	if _, err := fmt.Sscanf(strings.TrimPrefix(since, "v"), "%d", &sinceMajor); err != nil {
+		return false
+	}
+	return major > sinceMajor
+}
+
+// newManifest records the generated icons, and the shims with their body.
+func newManifest(info *datasetInfo, shims []*deprecatedIcon) *manifest {
+	m := &manifest{Version: info.version, Icons: make(map[string]*manifestIcon, len(info.icons)+len(shims))}
+	for _, icon := range info.icons {
+		m.Icons[icon.structName] = newManifestIcon(icon)
+	}
+	for _, shim := range shims {
+		entry := newManifestIcon(shim.generatedIcon)
+		entry.Body = shim.body
+		entry.RemovedIn = shim.removedIn
+		entry.Replacement = shim.replacement
+		m.Icons[shim.structName] = entry
+	}
+	return m
+}
+
+// newManifestIcon records a generated icon.
+func newManifestIcon(icon *generatedIcon) *manifestIcon {
+	return &manifestIcon{
+		Name:        icon.icon.Name,
+		Type:        icon.icon.Type,
+		Size:        icon.icon.Size.String(),
+		Directional: icon.icon.Directional,
+		Width:       icon.width,
+		Height:      icon.height,
+	}
+}
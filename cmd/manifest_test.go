@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeprecatedIcons(t *testing.T) {
+	info, err := parseDatasetInfo([]byte(`{"info":{"version":"2.2.0"},"icons":{
+		"code-bracket":{"body":"<path d=\"M4 4\"/>"}
+	},"aliases":{"code":{"parent":"code-bracket"}},`+heroiconsSuffixes+`}`), heroiconsSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous, err := parseDatasetInfo([]byte(`{"info":{"version":"2.1.5"},"icons":{
+		"code":{"body":"<path d=\"M4 4\"/>"},
+		"sun":{"body":"<path d=\"M3 3\"/>"}
+	},`+heroiconsSuffixes+`}`), heroiconsSet)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Icons removed since the last generation take their body from the previous dataset
+	m := &manifest{Version: "2.1.5", Icons: map[string]*manifestIcon{
+		"CodeBracket": {Name: "code-bracket", Type: "Outline", Size: "24", Width: 24, Height: 24},
+		"Code":        {Name: "code", Type: "Outline", Size: "24", Width: 24, Height: 24},
+		"Sun":         {Name: "sun", Type: "Outline", Size: "24", Width: 24, Height: 24},
+		"MoonMini":    {Name: "moon-20-solid", Type: "Mini", Size: "20", Width: 20, Height: 20, Body: `<path d="M2 2"/>`, RemovedIn: "2.0.0"},
+		"Star":        {Name: "star", Type: "Outline", Size: "24", Width: 24, Height: 24, Body: `<path d="M5 5"/>`, RemovedIn: "1.0.0"},
+		"Old":         {Name: "old", Type: "Outline", Size: "24", Width: 24, Height: 24, Body: `<path d="M6 6"/>`, RemovedIn: "2.1.0", Replacement: "Gone"},
+	}}
+
+	shims, err := deprecatedIcons(m, info, previous)
+	if err != nil {
+		t.Fatalf("deprecatedIcons() error = %v", err)
+	}
+
+	expected := []struct {
+		structName  string
+		removedIn   string
+		replacement string
+		body        string
+	}{
+		{"Code", "2.2.0", "CodeBracket", `<path d="M4 4"/>`},
+		{"MoonMini", "2.0.0", "", `<path d="M2 2"/>`},
+		{"Old", "2.1.0", "", `<path d="M6 6"/>`}, // The replacement was removed too
+		{"Sun", "2.2.0", "", `<path d="M3 3"/>`},
+		// Star was removed in the previous major version
+	}
+	if len(shims) != len(expected) {
+		t.Fatalf("deprecatedIcons() returned %d shims, want %d", len(shims), len(expected))
+	}
+	for i, want := range expected {
+		shim := shims[i]
+		if shim.structName != want.structName || shim.removedIn != want.removedIn || shim.replacement != want.replacement || shim.body != want.body {
+			t.Errorf("deprecatedIcons()[%d] = %s %s %q %s, want %s %s %q %s", i,
+				shim.structName, shim.removedIn, shim.replacement, shim.body,
+				want.structName, want.removedIn, want.replacement, want.body)
+		}
+	}
+
+	if _, err := deprecatedIcons(m, info, nil); err == nil || !strings.HasPrefix(err.Error(), "no previous artwork for the removed icon ") {
+		t.Errorf("deprecatedIcons() without a previous dataset error = %v", err)
+	}
+}
+
+func TestIsNewerMajor(t *testing.T) {
+	tests := []struct {
+		version, since string
+		expected       bool
+	}{
+		{"3.0.0", "2.2.0", true},
+		{"v3.0.0", "2.2.0", true},
+		{"2.9.0", "2.2.0", false},
+		{"2.2.0", "2.2.0", false},
+		{"", "2.2.0", false},
+		{"3.0.0", "unknown", false},
+	}
+
+	for _, tt := range tests {
+		if got := isNewerMajor(tt.version, tt.since); got != tt.expected {
+			t.Errorf("isNewerMajor(%q, %q) = %v, want %v", tt.version, tt.since, got, tt.expected)
+		}
+	}
+}
+
+func TestRunManifest(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "data", fmt.Sprintf(manifestFile, heroiconsPrefix))
+	cfg := config{cachePath: filepath.Join(dir, "data", "heroicons_cache.json"), outputDir: dir, pkg: packageName, manifest: manifestPath, set: heroiconsSet}
+
+	datasets := []string{
+		`{"info":{"version":"2.1.5"},"icons":{"code":{"body":"<path/>"},"sun":{"body":"<circle/>"}},` + heroiconsSuffixes + `}`,
+		`{"info":{"version":"2.2.0"},"icons":{"code-bracket":{"body":"<path/>"}},"aliases":{"code":{"parent":"code-bracket"}},` + heroiconsSuffixes + `}`,
+	}
+	for i, dataset := range datasets {
+		cfg.compare, cfg.input = cfg.input, filepath.Join(dir, fmt.Sprintf("icons-%d.json", i))
+		if err := os.WriteFile(cfg.input, []byte(dataset), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := run(cfg); err != nil {
+			t.Fatalf("run() error = %v", err)
+		}
+	}
+
+	generated, err := os.ReadFile(cfg.outputFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"\t// Deprecated: Removed in heroicons v2.2.0, use CodeBracket instead.\n\tCode = CodeBracket\n",
+		"\t// Deprecated: Removed in heroicons v2.2.0, without replacement.\n\tSun = &Icon{Name: \"sun\", Type: \"Outline\", Size: \"24\", body: \"<circle/>\"}\n",
+	} {
+		if !strings.Contains(string(generated), expected) {
+			t.Errorf("generated file = %q, want %q", generated, expected)
+		}
+	}
+
+	// The shims are persisted in the manifest, and kept by later generations
+	m, err := loadManifest(manifestPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Version != "2.2.0" || m.Icons["Sun"] == nil || m.Icons["Sun"].RemovedIn != "2.2.0" || m.Icons["Code"].Replacement != "CodeBracket" {
+		t.Errorf("loadManifest() = %+v", m)
+	}
+	// Only the shims record their body
+	if m.Icons["Sun"].Body != "<circle/>" || m.Icons["CodeBracket"].Body != "" {
+		t.Errorf("loadManifest() bodies = %q, %q", m.Icons["Sun"].Body, m.Icons["CodeBracket"].Body)
+	}
+	cfg.compare = ""
+	cfg.check = true
+	if err := run(cfg); err != nil {
+		t.Errorf("run() in check mode error = %v", err)
+	}
+
+	// An outdated manifest fails the check, and is left untouched
+	m.Version = "2.1.5"
+	if err := saveManifest(manifestPath, m); err != nil {
+		t.Fatal(err)
+	}
+	err = run(cfg)
+	if !errors.Is(err, errOutdated) || !strings.Contains(err.Error(), "\n+\t\"version\": \"2.2.0\",\n") {
+		t.Errorf("run() in check mode error = %v, want a manifest diff", err)
+	}
+	if current, err := loadManifest(manifestPath); err != nil || current.Version != "2.1.5" {
+		t.Errorf("run() in check mode wrote the manifest")
+	}
+}
+
+func TestLoadManifestErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), fmt.Sprintf(manifestFile, heroiconsPrefix))
+	if m, err := loadManifest(path); err != nil || len(m.Icons) != 0 {
+		t.Errorf("loadManifest() = %+v, %v, want an empty manifest", m, err)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadManifest(path); err == nil || !strings.HasPrefix(err.Error(), "parsing manifest ") {
+		t.Errorf("loadManifest() error = %v, want a parsing error", err)
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// maxAliasDepth bounds the resolution of aliases pointing at other aliases.
+const maxAliasDepth = 8
+
+// datasetInfo holds the metadata and icons of a dataset version.
+type datasetInfo struct {
+	version      string                    // info.version (e.g., "2.2.0")
+	lastModified int64                     // Unix time of the last dataset change
+	aliases      map[string]string         // Alias name to parent name
+	icons        map[string]*generatedIcon // Icons by name
+}
+
+// iconRename is an icon removed from the dataset and kept as an alias of another icon.
+type iconRename struct {
+	from, to *generatedIcon
+}
+
+// datasetReport lists the differences between two dataset versions.
+type datasetReport struct {
+	prefix   string // Iconify prefix of the dataset
+	old, new *datasetInfo
+	added    []*generatedIcon
+	removed  []*generatedIcon
+	renamed  []iconRename
+	changed  []*generatedIcon
+}
+
+// parseDatasetInfo parses the metadata and icons of a dataset.
+func parseDatasetInfo(jsonData []byte, set *iconSet) (*datasetInfo, error) {
+	icons, err := parseIcons(jsonData, set)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &datasetInfo{
+		version:      gjson.GetBytes(jsonData, "info.version").String(),
+		lastModified: gjson.GetBytes(jsonData, "lastModified").Int(),
+		aliases:      make(map[string]string),
+		icons:        icons,
+	}
+	gjson.GetBytes(jsonData, "aliases").ForEach(func(key, value gjson.Result) bool {
+		if parent := value.Get("parent").String(); parent != "" {
+			info.aliases[key.String()] = parent
+		}
+		return true
+	})
+	return info, nil
+}
+
+// resolveAlias returns the icon an alias points at, following aliases of aliases.
+func (d *datasetInfo) resolveAlias(name string) (*generatedIcon, bool) {
+	for range maxAliasDepth {
+		parent, ok := d.aliases[name]
+		if !ok {
+			return nil, false
+		}
+		if icon, ok := d.icons[parent]; ok {
+			return icon, true
+		}
+		name = parent
+	}
+	return nil, false
+}
+
+// compareDatasets reports the icons added, removed, renamed and changed between two datasets.
+// Removed icons kept as aliases in the new dataset are reported as renamed.
+func compareDatasets(oldData, newData []byte, set *iconSet) (*datasetReport, error) {
+	oldInfo, err := parseDatasetInfo(oldData, set)
+	if err != nil {
+		return nil, fmt.Errorf("parsing previous dataset: %w", err)
+	}
+	newInfo, err := parseDatasetInfo(newData, set)
+	if err != nil {
+		return nil, fmt.Errorf("parsing new dataset: %w", err)
+	}
+
+	report := &datasetReport{prefix: set.prefix, old: oldInfo, new: newInfo}
+	for name, icon := range oldInfo.icons {
+		current, found := newInfo.icons[name]
+		switch {
+		case !found:
+			if target, ok := newInfo.resolveAlias(name); ok {
+				report.renamed = append(report.renamed, iconRename{from: icon, to: target})
+			} else {
+				report.removed = append(report.removed, icon)
+			}
+		case current.body != icon.body || current.width != icon.width || current.height != icon.height:
+			report.changed = append(report.changed, current)
+		}
+	}
+	for name, icon := range newInfo.icons {
+		if _, found := oldInfo.icons[name]; !found {
+			report.added = append(report.added, icon)
+		}
+	}
+
+	for _, icons := range [][]*generatedIcon{report.added, report.removed, report.changed} {
+		sortGeneratedIcons(icons)
+	}
+	sort.Slice(report.renamed, func(i, j int) bool {
+		return report.renamed[i].from.structName < report.renamed[j].from.structName
+	})
+	return report, nil
+}
+
+// sortGeneratedIcons sorts icons by variable name.
+func sortGeneratedIcons(icons []*generatedIcon) {
+	sort.Slice(icons, func(i, j int) bool {
+		return icons[i].structName < icons[j].structName
+	})
+}
+
+// isEmpty reports whether the datasets have the same icons.
+func (r *datasetReport) isEmpty() bool {
+	return len(r.added) == 0 && len(r.removed) == 0 && len(r.renamed) == 0 && len(r.changed) == 0
+}
+
+// reportSection is a changie kind of the report with its change items.
+type reportSection struct {
+	kind  string
+	items []string
+}
+
+// sections groups the changes by changie kind, skipping empty kinds.
+// With deprecation shims, removed icons are reported as deprecated rather than removed.
+func (r *datasetReport) sections(shims bool) []reportSection {
+	var added, removed, changed []string
+	for _, icon := range r.added {
+		added = append(added, fmt.Sprintf("`%s` icon (`%s`)", icon.structName, icon.icon.Name))
+	}
+	for _, rename := range r.renamed {
+		format := "`%s` icon (`%s`), renamed to `%s` (`%s`)"
+		if shims {
+			format = "`%s` icon (`%s`), use `%s` (`%s`) instead"
+		}
+		removed = append(removed, fmt.Sprintf(format,
+			rename.from.structName, rename.from.icon.Name, rename.to.structName, rename.to.icon.Name))
+	}
+	for _, icon := range r.removed {
+		format := "`%s` icon (`%s`)"
+		if shims {
+			format = "`%s` icon (`%s`), removed upstream and kept until the next major version"
+		}
+		removed = append(removed, fmt.Sprintf(format, icon.structName, icon.icon.Name))
+	}
+	sort.Strings(removed)
+	for _, icon := range r.changed {
+		changed = append(changed, fmt.Sprintf("`%s` icon (`%s`) artwork", icon.structName, icon.icon.Name))
+	}
+
+	removedKind := "Removed"
+	if shims {
+		removedKind = "Deprecated"
+	}
+	var sections []reportSection
+	for _, section := range []reportSection{{"Added", added}, {"Changed", changed}, {removedKind, removed}} {
+		if len(section.items) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+// summary describes the dataset update, from the previous version to the new one.
+func (r *datasetReport) summary() string {
+	return fmt.Sprintf("Update the %s dataset from %s to %s", r.prefix, describeDataset(r.old), describeDataset(r.new))
+}
+
+// markdown formats the report as a summary followed by `### Kind` sections of `* Body` items,
+// e.g. for a pull request description.
+func (r *datasetReport) markdown(shims bool) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s.\n", r.summary())
+	for _, section := range r.sections(shims) {
+		fmt.Fprintf(&builder, "\n### %s\n\n", section.kind)
+		for _, item := range section.items {
+			fmt.Fprintf(&builder, "* %s\n", item)
+		}
+	}
+	return builder.String()
+}
+
+// changieFragment is an unreleased changie entry, written as a YAML file.
+type changieFragment struct {
+	name    string // File name, following the changie default fragment file format
+	content string
+}
+
+// changieFragments formats the report as one changie entry per kind, timestamped with now.
+// Each entry lists its items after the dataset summary, in a single-quoted YAML body.
+func (r *datasetReport) changieFragments(shims bool, now time.Time) []changieFragment {
+	var fragments []changieFragment
+	for _, section := range r.sections(shims) {
+		body := r.summary() + ": " + strings.Join(section.items, "; ")
+		fragments = append(fragments, changieFragment{
+			name: fmt.Sprintf("%s-%s.yaml", section.kind, now.Format("20060102-150405")),
+			content: fmt.Sprintf("kind: %s\nbody: '%s'\ntime: %s\n",
+				section.kind, strings.ReplaceAll(body, "'", "''"), now.Format(time.RFC3339Nano)),
+		})
+	}
+	return fragments
+}
+
+// describeDataset formats the version and last modification date of a dataset.
+func describeDataset(info *datasetInfo) string {
+	version := info.version
+	if version == "" {
+		version = "unknown version"
+	} else {
+		version = "v" + version
+	}
+	if info.lastModified == 0 {
+		return version
+	}
+	return fmt.Sprintf("%s (%s)", version, time.Unix(info.lastModified, 0).UTC().Format("2006-01-02"))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reportFixtures are two versions of a dataset: "sun" is removed, "code" is renamed to
+// "code-bracket", "moon" is redrawn and "star" is added.
+var reportFixtures = [2]string{
+	`{"info":{"version":"2.1.5"},"lastModified":1710000000,"icons":{
+		"moon":{"body":"<path d=\"M1 1\"/>"},
+		"moon-20-solid":{"body":"<path d=\"M2 2\"/>","width":20,"height":20},
+		"sun":{"body":"<path d=\"M3 3\"/>"},
+		"code":{"body":"<path d=\"M4 4\"/>"}
+	},` + heroiconsSuffixes + `}`,
+	`{"info":{"version":"2.2.0"},"lastModified":1721921294,"icons":{
+		"moon":{"body":"<path d=\"M1 2\"/>"},
+		"moon-20-solid":{"body":"<path d=\"M2 2\"/>","width":20,"height":20},
+		"code-bracket":{"body":"<path d=\"M4 4\"/>"},
+		"star":{"body":"<path d=\"M5 5\"/>"}
+	},"aliases":{"code":{"parent":"code-legacy"},"code-legacy":{"parent":"code-bracket"}},` + heroiconsSuffixes + `}`,
+}
+
+func TestCompareDatasets(t *testing.T) {
+	report, err := compareDatasets([]byte(reportFixtures[0]), []byte(reportFixtures[1]), heroiconsSet)
+	if err != nil {
+		t.Fatalf("compareDatasets() error = %v", err)
+	}
+
+	expected := "Update the heroicons dataset from v2.1.5 (2024-03-09) to v2.2.0 (2024-07-25).\n" +
+		"\n### Added\n\n" +
+		"* `CodeBracket` icon (`code-bracket`)\n" +
+		"* `Star` icon (`star`)\n" +
+		"\n### Changed\n\n" +
+		"* `Moon` icon (`moon`) artwork\n" +
+		"\n### Removed\n\n" +
+		"* `Code` icon (`code`), renamed to `CodeBracket` (`code-bracket`)\n" +
+		"* `Sun` icon (`sun`)\n"
+	if got := report.markdown(false); got != expected {
+		t.Errorf("markdown(false) = %q, want %q", got, expected)
+	}
+
+	expected = strings.Replace(expected, "### Removed\n\n"+
+		"* `Code` icon (`code`), renamed to `CodeBracket` (`code-bracket`)\n"+
+		"* `Sun` icon (`sun`)\n", "### Deprecated\n\n"+
+		"* `Code` icon (`code`), use `CodeBracket` (`code-bracket`) instead\n"+
+		"* `Sun` icon (`sun`), removed upstream and kept until the next major version\n", 1)
+	if got := report.markdown(true); got != expected {
+		t.Errorf("markdown(true) = %q, want %q", got, expected)
+	}
+}
+
+func TestChangieFragments(t *testing.T) {
+	report, err := compareDatasets([]byte(reportFixtures[0]), []byte(reportFixtures[1]), heroiconsSet)
+	if err != nil {
+		t.Fatalf("compareDatasets() error = %v", err)
+	}
+	report.new.version = "2.2.0'rc"
+	now := time.Date(2024, 7, 26, 9, 30, 5, 0, time.FixedZone("", 2*60*60))
+
+	summary := "Update the heroicons dataset from v2.1.5 (2024-03-09) to v2.2.0''rc (2024-07-25): "
+	expected := []changieFragment{
+		{"Added-20240726-093005.yaml", "kind: Added\nbody: '" + summary +
+			"`CodeBracket` icon (`code-bracket`); `Star` icon (`star`)'\ntime: 2024-07-26T09:30:05+02:00\n"},
+		{"Changed-20240726-093005.yaml", "kind: Changed\nbody: '" + summary +
+			"`Moon` icon (`moon`) artwork'\ntime: 2024-07-26T09:30:05+02:00\n"},
+		{"Deprecated-20240726-093005.yaml", "kind: Deprecated\nbody: '" + summary +
+			"`Code` icon (`code`), use `CodeBracket` (`code-bracket`) instead; " +
+			"`Sun` icon (`sun`), removed upstream and kept until the next major version'\ntime: 2024-07-26T09:30:05+02:00\n"},
+	}
+	got := report.changieFragments(true, now)
+	if len(got) != len(expected) {
+		t.Fatalf("changieFragments() = %q, want %q", got, expected)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("changieFragments()[%d] = %q, want %q", i, got[i], expected[i])
+		}
+	}
+
+	if got := report.changieFragments(false, now); len(got) != 3 || !strings.HasPrefix(got[2].content, "kind: Removed\n") {
+		t.Errorf("changieFragments(false) = %q, want a Removed entry", got)
+	}
+}
+
+func TestCompareDatasetsUnchanged(t *testing.T) {
+	report, err := compareDatasets([]byte(reportFixtures[1]), []byte(reportFixtures[1]), heroiconsSet)
+	if err != nil {
+		t.Fatalf("compareDatasets() error = %v", err)
+	}
+	if !report.isEmpty() {
+		t.Errorf("compareDatasets() = %+v, want no changes", report)
+	}
+}
+
+func TestCompareDatasetsErrors(t *testing.T) {
+	if _, err := compareDatasets([]byte(`{}`), []byte(reportFixtures[1]), heroiconsSet); err == nil || !strings.HasPrefix(err.Error(), "parsing previous dataset: ") {
+		t.Errorf("compareDatasets() error = %v, want a previous dataset error", err)
+	}
+	if _, err := compareDatasets([]byte(reportFixtures[0]), []byte(`{}`), heroiconsSet); err == nil || !strings.HasPrefix(err.Error(), "parsing new dataset: ") {
+		t.Errorf("compareDatasets() error = %v, want a new dataset error", err)
+	}
+}
+
+func TestRunReport(t *testing.T) {
+	dir := t.TempDir()
+	paths := [2]string{filepath.Join(dir, "old.json"), filepath.Join(dir, "new.json")}
+	for i, path := range paths {
+		if err := os.WriteFile(path, []byte(reportFixtures[i]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reportPath := filepath.Join(dir, "report.md")
+	changieDir := filepath.Join(dir, ".changes", "unreleased")
+	cfg := config{input: paths[1], cachePath: filepath.Join(dir, "heroicons_cache.json"), outputDir: dir, pkg: packageName, report: reportPath, changie: changieDir, compare: paths[0], set: heroiconsSet}
+	if err := run(cfg); err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+
+	report, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(report), "* `Star` icon (`star`)\n") {
+		t.Errorf("report = %q, want the added icon", report)
+	}
+
+	// Without a manifest, removed icons are not kept as shims
+	for _, pattern := range []string{"Added-*.yaml", "Changed-*.yaml", "Removed-*.yaml"} {
+		if matches, _ := filepath.Glob(filepath.Join(changieDir, pattern)); len(matches) != 1 {
+			t.Errorf("changie entries %s = %v, want one", pattern, matches)
+		}
+	}
+
+	// The cache copied from the previous local dataset is compared with
+	cfg.compare = ""
+	if err := run(cfg); err != nil {
+		t.Errorf("run() with the cache error = %v", err)
+	}
+
+	// Without a cache, there is no previous dataset
+	cfg.cachePath = filepath.Join(dir, "missing", "heroicons_cache.json")
+	if err := run(cfg); err == nil || err.Error() != "loading previous dataset: -report and -changie require -compare or a cached dataset" {
+		t.Errorf("run() error = %v", err)
+	}
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	heroicons "github.com/indaco/templheroicons"
+)
+
+// svgPresentationAttributes lists the presentation attributes of the <svg> wrapper
+// kept on a group around the body, since the wrapper is replaced when rendering.
+var svgPresentationAttributes = map[string]struct{}{
+	"fill": {}, "fill-rule": {}, "fill-opacity": {}, "clip-rule": {}, "opacity": {}, "color": {},
+	"stroke": {}, "stroke-width": {}, "stroke-linecap": {}, "stroke-linejoin": {},
+	"stroke-miterlimit": {}, "stroke-dasharray": {}, "stroke-opacity": {},
+}
+
+// svgPaintAttributes lists the paint attributes and style properties of the body elements.
+var svgPaintAttributes = map[string]struct{}{"fill": {}, "stroke": {}, "color": {}, "stop-color": {}}
+
+// svgTextEscaper escapes the text content of the body elements.
+var svgTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// svgNamePattern matches the characters replaced in icon names.
+var svgNamePattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// importedIcon is an Iconify icon normalized from an SVG file.
+type importedIcon struct {
+	Body   string `json:"body"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+}
+
+// parseImportFlags parses the arguments of the import subcommand into a config and
+// the directory of the SVG files. The Iconify JSON is written to the -input path,
+// which defaults to `<out>/<prefix>.json`.
+func parseImportFlags(args []string) (config, string, error) {
+	var dir string
+	cfg, setFlags, err := parseFlagSet("icons-maker import", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&dir, "dir", "", "directory of the SVG files to import")
+	})
+	if err != nil {
+		return config{}, "", err
+	}
+
+	switch {
+	case dir == "":
+		return config{}, "", fmt.Errorf("-dir is required")
+	case cfg.set.prefix == heroiconsPrefix:
+		return config{}, "", fmt.Errorf("-prefix is required and cannot be %q", heroiconsPrefix)
+	}
+	if !setFlags["input"] {
+		cfg.input = filepath.Join(cfg.outputDir, cfg.set.prefix+".json")
+	}
+	if isURL(cfg.input) {
+		return config{}, "", fmt.Errorf("-input must be a local file")
+	}
+	return cfg, dir, nil
+}
+
+// runImport normalizes the SVG files of a directory into an Iconify JSON dataset,
+// then generates the Go file with their icon definitions.
+func runImport(cfg config, dir string) error {
+	data, err := importSVGDirectory(dir, cfg.set.prefix)
+	if err != nil {
+		return err
+	}
+
+	if cfg.check {
+		current, err := os.ReadFile(cfg.input)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if diff := unifiedDiff(cfg.input, cfg.input+" (imported)", string(current), string(data)); diff != "" {
+			return fmt.Errorf("%w\n%s", errOutdated, diff)
+		}
+	} else {
+		if err := ensureDir(filepath.Dir(cfg.input)); err != nil {
+			return err
+		}
+		if err := os.WriteFile(cfg.input, data, 0644); err != nil {
+			return err
+		}
+		log.Printf("%s successfully created.\n", cfg.input)
+	}
+	return run(cfg)
+}
+
+// importSVGDirectory reads the `.svg` files of a directory into an Iconify JSON dataset.
+func importSVGDirectory(dir, prefix string) ([]byte, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	icons := make(map[string]importedIcon)
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".svg") {
+			continue
+		}
+		name := svgIconName(entry.Name())
+		if name == "" {
+			return nil, fmt.Errorf("%s: cannot derive an icon name", entry.Name())
+		}
+		if other, found := files[name]; found {
+			return nil, fmt.Errorf("%s and %s both import the %q icon", other, entry.Name(), name)
+		}
+		files[name] = entry.Name()
+
+		source, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		icon, err := normalizeSVG(source)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		icons[name] = icon
+	}
+	if len(icons) == 0 {
+		return nil, fmt.Errorf("no SVG files found in %s", dir)
+	}
+
+	return marshalIconSet(prefix, icons)
+}
+
+// svgIconName derives a kebab-case icon name from an SVG file name (e.g., "Brand Logo_v2.svg").
+func svgIconName(fileName string) string {
+	name := strings.ToLower(strings.TrimSuffix(fileName, filepath.Ext(fileName)))
+	return strings.Trim(svgNamePattern.ReplaceAllString(name, "-"), "-")
+}
+
+// marshalIconSet encodes the icons as an Iconify JSON dataset. The size shared by
+// most icons is set on the dataset, and other sizes on their icons.
+func marshalIconSet(prefix string, icons map[string]importedIcon) ([]byte, error) {
+	sizes := make(map[[2]int]int)
+	for _, icon := range icons {
+		sizes[[2]int{icon.Width, icon.Height}]++
+	}
+	var common [2]int
+	for size, count := range sizes {
+		if count > sizes[common] || (count == sizes[common] && (size[0] > common[0] || (size[0] == common[0] && size[1] > common[1]))) {
+			common = size
+		}
+	}
+	for name, icon := range icons {
+		if icon.Width == common[0] && icon.Height == common[1] {
+			icon.Width, icon.Height = 0, 0
+			icons[name] = icon
+		}
+	}
+
+	dataset := struct {
+		Prefix string                  `json:"prefix"`
+		Info   map[string]any          `json:"info"`
+		Icons  map[string]importedIcon `json:"icons"`
+		Width  int                     `json:"width"`
+		Height int                     `json:"height"`
+	}{
+		Prefix: prefix,
+		Info:   map[string]any{"name": prefix, "total": len(icons)},
+		Icons:  icons,
+		Width:  common[0],
+		Height: common[1],
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetEscapeHTML(false) // Keep the SVG bodies readable
+	encoder.SetIndent("", "\t")
+	if err := encoder.Encode(dataset); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// normalizeSVG converts an SVG document into an Iconify icon: the <svg> wrapper is stripped,
+// its viewBox gives the icon size, and hardcoded colors are replaced with currentColor.
+// Active content (e.g., scripts, event handlers) is rejected like heroicons.Register does.
+func normalizeSVG(source []byte) (importedIcon, error) {
+	root, body, err := splitSVG(source)
+	if err != nil {
+		return importedIcon{}, err
+	}
+
+	minX, minY, width, height, err := svgViewBox(root)
+	if err != nil {
+		return importedIcon{}, err
+	}
+
+	// Keep the presentation attributes of the wrapper on a group
+	var group strings.Builder
+	var names []string
+	for _, attr := range root.Attr {
+		if _, ok := svgPresentationAttributes[attr.Name.Local]; ok && attr.Name.Space == "" {
+			names = append(names, attr.Name.Local)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := replaceSVGColors(xml.Name{Local: name}, svgAttribute(root, name))
+		fmt.Fprintf(&group, ` %s="%s"`, name, escapeSVGAttribute(value))
+	}
+	if minX != 0 || minY != 0 {
+		fmt.Fprintf(&group, ` transform="translate(%s %s)"`, formatSVGNumber(-minX), formatSVGNumber(-minY))
+	}
+	if group.Len() > 0 {
+		body = "<g" + group.String() + ">" + body + "</g>"
+	}
+
+	// Generated icons are rendered as-is: reject the bodies able to run scripts
+	if err := heroicons.ValidateBody(body); err != nil {
+		return importedIcon{}, fmt.Errorf("unsafe SVG: %w", err)
+	}
+	return importedIcon{Body: body, Width: width, Height: height}, nil
+}
+
+// splitSVG returns the root <svg> element and the markup of its children, encoded again
+// from the XML tokens: comments and whitespace between tags are dropped, attributes are
+// double-quoted, and hardcoded colors are replaced with currentColor. <style> elements
+// are rejected, as their rules cannot be rewritten reliably.
+func splitSVG(source []byte) (xml.StartElement, string, error) {
+	// Raw tokens keep the namespace prefixes of the body (e.g., xlink:href)
+	decoder := xml.NewDecoder(bytes.NewReader(source))
+	var root xml.StartElement
+	var body strings.Builder
+	var open []xml.Name // Elements open in the body
+	started := false    // The root element was found
+	pending := false    // The last start tag is left open, to self-close empty elements
+	for {
+		token, err := decoder.RawToken()
+		switch {
+		case err == io.EOF && !started:
+			return root, "", fmt.Errorf("missing <svg> element")
+		case err == io.EOF:
+			return root, "", fmt.Errorf("invalid SVG: unexpected end of file")
+		case err != nil:
+			return root, "", fmt.Errorf("invalid SVG: %w", err)
+		}
+
+		if pending {
+			pending = false
+			if end, ok := token.(xml.EndElement); ok && end.Name == open[len(open)-1] {
+				body.WriteString("/>")
+				open = open[:len(open)-1]
+				continue
+			}
+			body.WriteByte('>')
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			if !started {
+				if element.Name.Local != "svg" {
+					return root, "", fmt.Errorf("unexpected root element <%s>", element.Name.Local)
+				}
+				root, started = element.Copy(), true
+				continue
+			}
+			if element.Name.Local == "style" {
+				return root, "", fmt.Errorf("unsupported <style> element, use presentation attributes instead")
+			}
+			body.WriteString("<" + svgQualifiedName(element.Name))
+			for _, attr := range element.Attr {
+				fmt.Fprintf(&body, ` %s="%s"`, svgQualifiedName(attr.Name), escapeSVGAttribute(replaceSVGColors(attr.Name, attr.Value)))
+			}
+			open = append(open, element.Name)
+			pending = true
+		case xml.EndElement:
+			if len(open) == 0 {
+				if element.Name != root.Name {
+					return root, "", fmt.Errorf("invalid SVG: <%s> closed by </%s>", svgQualifiedName(root.Name), svgQualifiedName(element.Name))
+				}
+				return root, body.String(), nil
+			}
+			if name := open[len(open)-1]; element.Name != name {
+				return root, "", fmt.Errorf("invalid SVG: <%s> closed by </%s>", svgQualifiedName(name), svgQualifiedName(element.Name))
+			}
+			open = open[:len(open)-1]
+			body.WriteString("</" + svgQualifiedName(element.Name) + ">")
+		case xml.CharData:
+			if started && len(bytes.TrimSpace(element)) > 0 {
+				body.WriteString(svgTextEscaper.Replace(string(element)))
+			}
+		}
+		// Comments, processing instructions and directives are dropped
+	}
+}
+
+// svgQualifiedName formats a raw token name with its namespace prefix.
+func svgQualifiedName(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return name.Space + ":" + name.Local
+}
+
+// svgViewBox returns the viewBox of the root element, or its width and height without viewBox.
+// The icon must be square, as the rendering pipeline uses square viewBoxes.
+func svgViewBox(root xml.StartElement) (minX, minY float64, width, height int, err error) {
+	var w, h float64
+	if viewBox := svgAttribute(root, "viewBox"); viewBox != "" {
+		fields := strings.FieldsFunc(viewBox, func(r rune) bool { return r == ' ' || r == ',' })
+		values := make([]float64, len(fields))
+		for i, field := range fields {
+			if values[i], err = strconv.ParseFloat(field, 64); err != nil {
+				break
+			}
+		}
+		if len(fields) != 4 || err != nil {
+			return 0, 0, 0, 0, fmt.Errorf("invalid viewBox %q", viewBox)
+		}
+		minX, minY, w, h = values[0], values[1], values[2], values[3]
+	} else {
+		w, _ = strconv.ParseFloat(strings.TrimSuffix(svgAttribute(root, "width"), "px"), 64)
+		h, _ = strconv.ParseFloat(strings.TrimSuffix(svgAttribute(root, "height"), "px"), 64)
+	}
+
+	switch {
+	case w <= 0 || h <= 0:
+		return 0, 0, 0, 0, fmt.Errorf("missing viewBox or pixel width and height")
+	case w != float64(int(w)) || h != float64(int(h)):
+		return 0, 0, 0, 0, fmt.Errorf("fractional viewBox size %sx%s", formatSVGNumber(w), formatSVGNumber(h))
+	case w != h:
+		return 0, 0, 0, 0, fmt.Errorf("non-square viewBox %sx%s", formatSVGNumber(w), formatSVGNumber(h))
+	}
+	return minX, minY, int(w), int(h), nil
+}
+
+// svgAttribute returns the value of an attribute of the element.
+func svgAttribute(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name && attr.Name.Space == "" {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// replaceSVGColors returns the value of an attribute with its hardcoded colors replaced
+// with currentColor: the value of a paint attribute, or the paint declarations of a style.
+func replaceSVGColors(name xml.Name, value string) string {
+	if name.Space != "" {
+		return value
+	}
+	if name.Local == "style" {
+		return replaceStyleColors(value)
+	}
+	if _, ok := svgPaintAttributes[name.Local]; ok {
+		return replacePaintColor(value)
+	}
+	return value
+}
+
+// replaceStyleColors replaces the hardcoded colors of the paint declarations of a style
+// attribute (e.g., "fill: #000; opacity: .5"), keeping their priority.
+func replaceStyleColors(style string) string {
+	declarations := strings.Split(style, ";")
+	for i, declaration := range declarations {
+		property, value, found := strings.Cut(declaration, ":")
+		if _, ok := svgPaintAttributes[strings.ToLower(strings.TrimSpace(property))]; !found || !ok {
+			continue
+		}
+		value, priority, important := strings.Cut(value, "!")
+		if color := replacePaintColor(value); color != value {
+			declarations[i] = property + ":" + color
+			if important {
+				declarations[i] += " !" + priority
+			}
+		}
+	}
+	return strings.Join(declarations, ";")
+}
+
+// replacePaintColor returns currentColor for a hardcoded color, keeping `none`,
+// `transparent`, `currentColor`, `inherit` and references (e.g., `url(#gradient)`).
+func replacePaintColor(value string) string {
+	switch color := strings.ToLower(strings.TrimSpace(value)); {
+	case color == "none", color == "transparent", color == "currentcolor", color == "inherit",
+		strings.HasPrefix(color, "url("):
+		return value
+	}
+	return "currentColor"
+}
+
+// escapeSVGAttribute escapes an attribute value decoded by encoding/xml.
+func escapeSVGAttribute(value string) string {
+	var buffer strings.Builder
+	_ = xml.EscapeText(&buffer, []byte(value))
+	return strings.ReplaceAll(buffer.String(), `"`, "&#34;")
+}
+
+// formatSVGNumber formats a coordinate without trailing zeros.
+func formatSVGNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSVG(t *testing.T) {
+	tests := []struct {
+		name     string
+		source   string
+		expected importedIcon
+	}{
+		{
+			name:     "Strips the wrapper",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path d="M1 1"/></svg>`,
+			expected: importedIcon{Body: `<path d="M1 1"/>`, Width: 24, Height: 24},
+		},
+		{
+			name: "Strips the prolog, comments and whitespace",
+			source: `<?xml version="1.0"?>
+<!-- Exported -->
+<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24">
+	<!-- Layer 1 -->
+	<path d="M1 1"/>
+	<path d="M2 2"/>
+</svg>`,
+			expected: importedIcon{Body: `<path d="M1 1"/><path d="M2 2"/>`, Width: 24, Height: 24},
+		},
+		{
+			name:     "Keeps presentation attributes on a group",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" stroke-width="1.5" fill="none" stroke="#000" class="icon"><path d="M1 1"/></svg>`,
+			expected: importedIcon{Body: `<g fill="none" stroke="currentColor" stroke-width="1.5"><path d="M1 1"/></g>`, Width: 24, Height: 24},
+		},
+		{
+			name:     "Replaces hardcoded colors",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 20 20"><path fill="#0F172A" stroke="rgb(0, 0, 0)" d="M1 1"/><path fill="none" stroke="currentColor" d="M2 2"/><path fill="url(#a)" stop-color="red" d="M3 3"/></svg>`,
+			expected: importedIcon{Body: `<path fill="currentColor" stroke="currentColor" d="M1 1"/><path fill="none" stroke="currentColor" d="M2 2"/><path fill="url(#a)" stop-color="currentColor" d="M3 3"/>`, Width: 20, Height: 20},
+		},
+		{
+			name:     "Rewrites single-quoted attributes",
+			source:   `<svg xmlns='http://www.w3.org/2000/svg' viewBox='0 0 24 24' stroke='#000'><path fill='#0F172A' d='M1 1'/><text x='1'>A &amp; B</text></svg>`,
+			expected: importedIcon{Body: `<g stroke="currentColor"><path fill="currentColor" d="M1 1"/><text x="1">A &amp; B</text></g>`, Width: 24, Height: 24},
+		},
+		{
+			name:     "Replaces colors in style declarations",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path style="fill:#0F172A; stroke: none;opacity:.5" d="M1 1"/><stop style="stop-color: red !important"/></svg>`,
+			expected: importedIcon{Body: `<path style="fill:currentColor; stroke: none;opacity:.5" d="M1 1"/><stop style="stop-color:currentColor !important"/>`, Width: 24, Height: 24},
+		},
+		{
+			name:     "Keeps namespace prefixes",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" viewBox="0 0 24 24"><use xlink:href="#a" fill="red"></use></svg>`,
+			expected: importedIcon{Body: `<use xlink:href="#a" fill="currentColor"/>`, Width: 24, Height: 24},
+		},
+		{
+			name:     "Translates the viewBox origin",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="-2 1.5 16 16"><path d="M1 1"/></svg>`,
+			expected: importedIcon{Body: `<g transform="translate(2 -1.5)"><path d="M1 1"/></g>`, Width: 16, Height: 16},
+		},
+		{
+			name:     "Uses the pixel size without viewBox",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" width="16px" height="16px"><path d="M1 1"/></svg>`,
+			expected: importedIcon{Body: `<path d="M1 1"/>`, Width: 16, Height: 16},
+		},
+		{
+			name:     "Keeps nested svg elements",
+			source:   `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0,0,24,24"><svg x="2"><path d="M1 1"/></svg></svg>`,
+			expected: importedIcon{Body: `<svg x="2"><path d="M1 1"/></svg>`, Width: 24, Height: 24},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			icon, err := normalizeSVG([]byte(tt.source))
+			if err != nil {
+				t.Fatalf("normalizeSVG() error = %v", err)
+			}
+			if icon != tt.expected {
+				t.Errorf("normalizeSVG() = %+v, want %+v", icon, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeSVGErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		source        string
+		expectedError string
+	}{
+		{"Not an SVG", `<html></html>`, "unexpected root element <html>"},
+		{"Empty file", ``, "missing <svg> element"},
+		{"Malformed", `<svg viewBox="0 0 24 24"><path>`, "invalid SVG"},
+		{"Mismatched tags", `<svg viewBox="0 0 24 24"><g></path></svg>`, "invalid SVG: <g> closed by </path>"},
+		{"Script", `<svg viewBox="0 0 24 24"><script>alert(1)</script></svg>`, "unsafe SVG: disallowed <script> element"},
+		{"Event handler", `<svg viewBox="0 0 24 24"><path onclick="alert(1)" d="M1 1"/></svg>`, "unsafe SVG: disallowed onclick event handler on <path>"},
+		{"JavaScript link", `<svg viewBox="0 0 24 24"><a href="javascript:alert(1)"><path d="M1 1"/></a></svg>`, "unsafe SVG: disallowed javascript: URL in the href attribute of <a>"},
+		{"Style element", `<svg viewBox="0 0 24 24"><style>path { fill: red }</style><path d="M1 1"/></svg>`, "unsupported <style> element"},
+		{"Invalid viewBox", `<svg viewBox="0 0 24"></svg>`, `invalid viewBox "0 0 24"`},
+		{"Missing size", `<svg width="100%" height="100%"></svg>`, "missing viewBox or pixel width and height"},
+		{"Non-square", `<svg viewBox="0 0 24 20"></svg>`, "non-square viewBox 24x20"},
+		{"Fractional", `<svg viewBox="0 0 23.5 23.5"></svg>`, "fractional viewBox size 23.5x23.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := normalizeSVG([]byte(tt.source))
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+				t.Errorf("normalizeSVG() error = %v, want %q", err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestSVGIconName(t *testing.T) {
+	tests := []struct {
+		fileName string
+		expected string
+	}{
+		{"rocket.svg", "rocket"},
+		{"Brand Logo.svg", "brand-logo"},
+		{"rocket_launch.SVG", "rocket-launch"},
+		{"--Arrow  Up (v2)--.svg", "arrow-up-v2"},
+		{"_.svg", ""},
+	}
+
+	for _, tt := range tests {
+		if result := svgIconName(tt.fileName); result != tt.expected {
+			t.Errorf("svgIconName(%q) = %q, want %q", tt.fileName, result, tt.expected)
+		}
+	}
+}
+
+func TestParseImportFlagsErrors(t *testing.T) {
+	tests := []struct {
+		args          []string
+		expectedError string
+	}{
+		{[]string{"-prefix", "acme"}, "-dir is required"},
+		{[]string{"-dir", "icons"}, `-prefix is required and cannot be "heroicons"`},
+		{[]string{"-dir", "icons", "-prefix", "acme", "-input", "https://example.com/acme.json"}, "-input must be a local file"},
+	}
+
+	for _, tt := range tests {
+		if _, _, err := parseImportFlags(tt.args); err == nil || err.Error() != tt.expectedError {
+			t.Errorf("parseImportFlags(%q) error = %v, want %q", tt.args, err, tt.expectedError)
+		}
+	}
+}
+
+func TestRunImport(t *testing.T) {
+	dir := t.TempDir()
+	cfg, svgDir, err := parseImportFlags([]string{"-prefix", "acme", "-dir", filepath.Join("testdata", "svg"), "-out", dir})
+	if err != nil {
+		t.Fatalf("parseImportFlags() error = %v", err)
+	}
+	if cfg.input != filepath.Join(dir, "acme.json") {
+		t.Errorf("parseImportFlags() input = %q, want the Iconify JSON in the output directory", cfg.input)
+	}
+	if err := runImport(cfg, svgDir); err != nil {
+		t.Fatalf("runImport() error = %v", err)
+	}
+
+	dataset, err := os.ReadFile(cfg.input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := parseDatasetInfo(dataset, cfg.set)
+	if err != nil {
+		t.Fatalf("parseDatasetInfo() error = %v", err)
+	}
+	if len(info.icons) != 4 {
+		t.Errorf("imported %d icons, want 4", len(info.icons))
+	}
+
+	generated, err := os.ReadFile(cfg.outputFilePath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"package acme\n",
+		"\tBadge = templheroicons.NewIcon(\"badge\", \"Outline\", 16, \"<path fill=\\\"currentColor\\\" d=\\\"M8 1l7 7-7 7-7-7z\\\"/>\")\n",
+		"\t// BrandLogo is the Outline variant of the \"brand-logo\" icon, 24x24.\n",
+		"\tSpark = templheroicons.NewIcon(\"spark\", \"Outline\", 24, \"<path id=\\\"s\\\" style=\\\"fill:currentColor; stroke: none\\\" d=\\\"M12 2l3 7 7 3-7 3-3 7-3-7-7-3 7-3z\\\"/><use xlink:href=\\\"#s\\\" fill=\\\"currentColor\\\" transform=\\\"scale(.5)\\\"/>\")\n",
+		"\tRocketLaunch = templheroicons.NewIcon(\"rocket-launch\", \"Outline\", 20, \"<g transform=\\\"translate(-2 -2)\\\">",
+	} {
+		if !strings.Contains(string(generated), expected) {
+			t.Errorf("generated file = %q, want %q", generated, expected)
+		}
+	}
+
+	// Importing the same files again is up to date
+	cfg.check = true
+	if err := runImport(cfg, svgDir); err != nil {
+		t.Errorf("runImport() in check mode error = %v", err)
+	}
+}
+
+func TestRunImportErrors(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"icon-one.svg": `<svg viewBox="0 0 24 24"><path d="M1 1"/></svg>`,
+		"Icon One.svg": `<svg viewBox="0 0 24 24"><path d="M2 2"/></svg>`,
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, _, err := parseImportFlags([]string{"-prefix", "acme", "-dir", dir, "-out", t.TempDir()})
+	if err != nil {
+		t.Fatalf("parseImportFlags() error = %v", err)
+	}
+	if err := runImport(cfg, dir); err == nil || err.Error() != `Icon One.svg and icon-one.svg both import the "icon-one" icon` {
+		t.Errorf("runImport() error = %v", err)
+	}
+	if err := runImport(cfg, t.TempDir()); err == nil || !strings.HasPrefix(err.Error(), "no SVG files found in ") {
+		t.Errorf("runImport() error = %v", err)
+	}
+
+	// Active content fails the import with the file name
+	evilDir := t.TempDir()
+	evil := `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><script>alert(2)</script><path onclick="alert(3)" d="M1 1"/></svg>`
+	if err := os.WriteFile(filepath.Join(evilDir, "evil.svg"), []byte(evil), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runImport(cfg, evilDir); err == nil || err.Error() != "evil.svg: unsafe SVG: disallowed <script> element" {
+		t.Errorf("runImport() error = %v", err)
+	}
+	if _, err := os.Stat(cfg.outputFilePath()); !os.IsNotExist(err) {
+		t.Errorf("runImport() generated %s from an unsafe SVG", cfg.outputFilePath())
+	}
+}
@@ -0,0 +1,154 @@
+package templheroicons
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Color represents a CSS color value applied to the icon (e.g., "#0000FF", "rgb(0, 0, 255)").
+type Color string
+
+// CurrentColor makes the icon inherit the text color of its parent.
+const CurrentColor Color = "currentColor"
+
+// String returns the string representation of a Color.
+func (c Color) String() string {
+	return string(c)
+}
+
+// Hex returns a hex color (e.g., Hex("#22d3ee") or Hex("22d3ee")).
+func Hex(value string) Color {
+	return Color("#" + strings.TrimPrefix(value, "#"))
+}
+
+// RGB returns an `rgb()` color from its red, green and blue channels.
+func RGB(r, g, b uint8) Color {
+	return Color(fmt.Sprintf("rgb(%d, %d, %d)", r, g, b))
+}
+
+// HSL returns an `hsl()` color from its hue (degrees), saturation and lightness (percentages).
+func HSL(h, s, l float64) Color {
+	return Color(fmt.Sprintf("hsl(%s, %s%%, %s%%)", formatFloat(h), formatFloat(s), formatFloat(l)))
+}
+
+// Var returns a color referencing a CSS custom property (e.g., Var("--brand")).
+func Var(name string) Color {
+	return Color("var(--" + strings.TrimPrefix(name, "--") + ")")
+}
+
+// ParseColor validates a CSS color and returns it as a Color.
+// It accepts hex colors, named colors, `currentColor`, `transparent`, CSS color
+// functions (rgb, hsl, hwb, lab, lch, oklab, oklch, color, color-mix) and `var()` references.
+func ParseColor(value string) (Color, error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		return "", fmt.Errorf("invalid color: empty value")
+	case strings.HasPrefix(value, "#"):
+		if !isHexColor(value[1:]) {
+			return "", fmt.Errorf("invalid color %q: malformed hex value", value)
+		}
+	case strings.HasSuffix(value, ")"):
+		if err := validateColorFunction(value); err != nil {
+			return "", fmt.Errorf("invalid color %q: %w", value, err)
+		}
+	default:
+		if _, ok := namedColors[strings.ToLower(value)]; !ok {
+			return "", fmt.Errorf("invalid color %q: unknown color name", value)
+		}
+	}
+	return Color(value), nil
+}
+
+// isHexColor reports whether the digits form a 3, 4, 6 or 8 digit hex color.
+func isHexColor(digits string) bool {
+	switch len(digits) {
+	case 3, 4, 6, 8:
+	default:
+		return false
+	}
+	_, err := strconv.ParseUint(digits, 16, 32)
+	return err == nil
+}
+
+// colorFunctions lists the CSS functions accepted as color values.
+var colorFunctions = map[string]struct{}{
+	"rgb": {}, "rgba": {}, "hsl": {}, "hsla": {}, "hwb": {},
+	"lab": {}, "lch": {}, "oklab": {}, "oklch": {}, "color": {},
+	"color-mix": {}, "var": {},
+}
+
+// validateColorFunction checks a CSS color function such as `rgb(0 0 255 / 50%)`.
+// Arguments are limited to numbers, units, identifiers, custom property names and
+// nested functions, which rules out any character able to escape an attribute.
+func validateColorFunction(value string) error {
+	open := strings.IndexByte(value, '(')
+	if open <= 0 {
+		return fmt.Errorf("malformed function")
+	}
+	if _, ok := colorFunctions[strings.ToLower(value[:open])]; !ok {
+		return fmt.Errorf("unsupported function %q", value[:open])
+	}
+
+	depth := 0
+	for _, r := range value[open:] {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return fmt.Errorf("unbalanced parentheses")
+			}
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune(" .,%/+-_#", r):
+		default:
+			return fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	if depth != 0 {
+		return fmt.Errorf("unbalanced parentheses")
+	}
+	return nil
+}
+
+// formatFloat formats a float without trailing zeros.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// namedColors lists the CSS color keywords (lowercase).
+var namedColors = map[string]struct{}{
+	"currentcolor": {}, "transparent": {},
+	"aliceblue": {}, "antiquewhite": {}, "aqua": {}, "aquamarine": {}, "azure": {},
+	"beige": {}, "bisque": {}, "black": {}, "blanchedalmond": {}, "blue": {},
+	"blueviolet": {}, "brown": {}, "burlywood": {}, "cadetblue": {}, "chartreuse": {},
+	"chocolate": {}, "coral": {}, "cornflowerblue": {}, "cornsilk": {}, "crimson": {},
+	"cyan": {}, "darkblue": {}, "darkcyan": {}, "darkgoldenrod": {}, "darkgray": {},
+	"darkgreen": {}, "darkgrey": {}, "darkkhaki": {}, "darkmagenta": {}, "darkolivegreen": {},
+	"darkorange": {}, "darkorchid": {}, "darkred": {}, "darksalmon": {}, "darkseagreen": {},
+	"darkslateblue": {}, "darkslategray": {}, "darkslategrey": {}, "darkturquoise": {}, "darkviolet": {},
+	"deeppink": {}, "deepskyblue": {}, "dimgray": {}, "dimgrey": {}, "dodgerblue": {},
+	"firebrick": {}, "floralwhite": {}, "forestgreen": {}, "fuchsia": {}, "gainsboro": {},
+	"ghostwhite": {}, "gold": {}, "goldenrod": {}, "gray": {}, "green": {},
+	"greenyellow": {}, "grey": {}, "honeydew": {}, "hotpink": {}, "indianred": {},
+	"indigo": {}, "ivory": {}, "khaki": {}, "lavender": {}, "lavenderblush": {},
+	"lawngreen": {}, "lemonchiffon": {}, "lightblue": {}, "lightcoral": {}, "lightcyan": {},
+	"lightgoldenrodyellow": {}, "lightgray": {}, "lightgreen": {}, "lightgrey": {}, "lightpink": {},
+	"lightsalmon": {}, "lightseagreen": {}, "lightskyblue": {}, "lightslategray": {}, "lightslategrey": {},
+	"lightsteelblue": {}, "lightyellow": {}, "lime": {}, "limegreen": {}, "linen": {},
+	"magenta": {}, "maroon": {}, "mediumaquamarine": {}, "mediumblue": {}, "mediumorchid": {},
+	"mediumpurple": {}, "mediumseagreen": {}, "mediumslateblue": {}, "mediumspringgreen": {}, "mediumturquoise": {},
+	"mediumvioletred": {}, "midnightblue": {}, "mintcream": {}, "mistyrose": {}, "moccasin": {},
+	"navajowhite": {}, "navy": {}, "oldlace": {}, "olive": {}, "olivedrab": {},
+	"orange": {}, "orangered": {}, "orchid": {}, "palegoldenrod": {}, "palegreen": {},
+	"paleturquoise": {}, "palevioletred": {}, "papayawhip": {}, "peachpuff": {}, "peru": {},
+	"pink": {}, "plum": {}, "powderblue": {}, "purple": {}, "rebeccapurple": {},
+	"red": {}, "rosybrown": {}, "royalblue": {}, "saddlebrown": {}, "salmon": {},
+	"sandybrown": {}, "seagreen": {}, "seashell": {}, "sienna": {}, "silver": {},
+	"skyblue": {}, "slateblue": {}, "slategray": {}, "slategrey": {}, "snow": {},
+	"springgreen": {}, "steelblue": {}, "tan": {}, "teal": {}, "thistle": {},
+	"tomato": {}, "turquoise": {}, "violet": {}, "wheat": {}, "white": {},
+	"whitesmoke": {}, "yellow": {}, "yellowgreen": {},
+}
@@ -0,0 +1,101 @@
+package templheroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestColor_Constructors(t *testing.T) {
+	tests := []struct {
+		name     string
+		color    Color
+		expected string
+	}{
+		{"Hex with hash", Hex("#22d3ee"), "#22d3ee"},
+		{"Hex without hash", Hex("22d3ee"), "#22d3ee"},
+		{"RGB", RGB(34, 211, 238), "rgb(34, 211, 238)"},
+		{"HSL", HSL(187.5, 85.7, 53.3), "hsl(187.5, 85.7%, 53.3%)"},
+		{"Var with dashes", Var("--brand"), "var(--brand)"},
+		{"Var without dashes", Var("brand"), "var(--brand)"},
+		{"CurrentColor", CurrentColor, "currentColor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.color.String() != tt.expected {
+				t.Errorf("got %q, want %q", tt.color, tt.expected)
+			}
+			if _, err := ParseColor(tt.color.String()); err != nil {
+				t.Errorf("ParseColor(%q) unexpected error: %v", tt.color, err)
+			}
+		})
+	}
+}
+
+func TestColor_ParseColor(t *testing.T) {
+	tests := []struct {
+		value         string
+		expected      Color
+		expectedError string
+	}{
+		{value: "#fff", expected: "#fff"},
+		{value: "#FF0000", expected: "#FF0000"},
+		{value: "#ff000080", expected: "#ff000080"},
+		{value: " red ", expected: "red"},
+		{value: "RebeccaPurple", expected: "RebeccaPurple"},
+		{value: "transparent", expected: "transparent"},
+		{value: "currentColor", expected: "currentColor"},
+		{value: "rgb(0 0 255 / 50%)", expected: "rgb(0 0 255 / 50%)"},
+		{value: "oklch(0.789 0.154 211.53)", expected: "oklch(0.789 0.154 211.53)"},
+		{value: "var(--brand, #fff)", expected: "var(--brand, #fff)"},
+		{value: "color-mix(in srgb, red 50%, var(--brand))", expected: "color-mix(in srgb, red 50%, var(--brand))"},
+		{value: "", expectedError: "empty value"},
+		{value: "#ggg", expectedError: "malformed hex value"},
+		{value: "#12345", expectedError: "malformed hex value"},
+		{value: "blurple", expectedError: "unknown color name"},
+		{value: "url(#x)", expectedError: "unsupported function"},
+		{value: "rgb(0,0,0", expectedError: "unknown color name"},
+		{value: "rgb((0)", expectedError: "unbalanced parentheses"},
+		{value: "rgb(0))", expectedError: "unbalanced parentheses"},
+		{value: `red" onload="alert(1)`, expectedError: "unsupported function"},
+		{value: `rgb(0,0,0)" onload="alert(1)`, expectedError: "unexpected character"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			color, err := ParseColor(tt.value)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("ParseColor(%q) error = %v, want %q", tt.value, err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColor(%q) unexpected error: %v", tt.value, err)
+			}
+			if color != tt.expected {
+				t.Errorf("ParseColor(%q) = %q, want %q", tt.value, color, tt.expected)
+			}
+		})
+	}
+}
+
+func TestColor_SetColorRejectsInjection(t *testing.T) {
+	icon := &Icon{Name: "test-icon", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	result := makeSVGTag(icon.Config().SetColor(`red" onload="alert(1)`).GetIcon())
+	if !strings.HasPrefix(result, "<!-- Error: invalid color") || strings.Contains(result, "<svg") {
+		t.Errorf("expected error comment, got %q", result)
+	}
+
+	result = makeSVGTag(icon.Config().SetColor(`--><script>alert(1)</script>`).GetIcon())
+	if strings.Contains(result, "<script>") || strings.Count(result, "-->") != 1 {
+		t.Errorf("error comment was broken out of: %q", result)
+	}
+
+	result = makeSVGTag(icon.Config().SetColorValue(Var("--brand")).GetIcon())
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor" color="var(--brand)"><path d="M1 1"/></svg>`
+	if result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+}
@@ -0,0 +1,73 @@
+package templheroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestContainer_Shapes(t *testing.T) {
+	icon := &Icon{Name: "sparkles", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+	mini := &Icon{Name: "sparkles-20-solid", Size: "20", Type: "Mini", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Circle container",
+			builder:  icon.Config().WithContainer(Circle, "cyan-100", 6).SetColor("cyan-700").SetSize(48),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 36 36" fill="none" stroke-width="1.5" stroke="currentColor" color="#0e7490"><circle cx="18" cy="18" r="18" fill="#cffafe" stroke="none"/><g transform="translate(6 6)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Rounded square container",
+			builder:  mini.Config().WithContainer(RoundedSquare, "#000", 5),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 30 30" fill="currentColor"><rect width="30" height="30" rx="6" fill="#000" stroke="none"/><g transform="translate(5 5)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Square container with a transformed icon",
+			builder:  mini.Config().WithContainer(Square, CurrentColor, 0).Rotate(90),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20" fill="currentColor"><rect width="20" height="20" fill="currentColor" stroke="none"/><g transform="translate(0 0)"><g transform="rotate(90 10 10)"><path d="M1 1"/></g></g></svg>`,
+		},
+		{
+			name:     "Absolute stroke width accounts for the padding",
+			builder:  icon.Config().WithContainer(Circle, "white", 12).SetSize(48).AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48" fill="none" stroke-width="1.5" stroke="currentColor"><circle cx="24" cy="24" r="24" fill="white" stroke="none"/><g transform="translate(12 12)"><path d="M1 1"/></g></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if icon.container != nil {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestContainer_InvalidValues(t *testing.T) {
+	icon := &Icon{Name: "sparkles", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name          string
+		builder       *IconBuilder
+		expectedError string
+	}{
+		{"Invalid shape", icon.Config().WithContainer(Shape(9), "#fff", 4), "invalid container shape 9"},
+		{"Negative padding", icon.Config().WithContainer(Circle, "#fff", -1), "invalid container padding -1"},
+		{"Invalid background", icon.Config().WithContainer(Circle, `#fff" onload="x`, 4), "invalid container background"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expectedError) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package templheroicons
+
+import "context"
+
+// Direction represents the text direction of the document.
+type Direction int
+
+const (
+	LTR Direction = iota // Left-to-right (default)
+	RTL                  // Right-to-left (e.g., Arabic, Hebrew)
+)
+
+// directionKey is the context key holding the Direction.
+type directionKey struct{}
+
+// WithDirection returns a context rendering icons for the given text direction.
+// With RTL, directional icons (e.g., ArrowLeft, ChevronRight) are mirrored automatically.
+func WithDirection(ctx context.Context, direction Direction) context.Context {
+	return context.WithValue(ctx, directionKey{}, direction)
+}
+
+// DirectionFromContext returns the text direction set with WithDirection, or LTR.
+func DirectionFromContext(ctx context.Context) Direction {
+	if direction, ok := ctx.Value(directionKey{}).(Direction); ok {
+		return direction
+	}
+	return LTR
+}
+
+// forDirection returns the icon to render for the text direction of the context,
+// mirroring directional icons in RTL. The mirror applies to the rendered artwork,
+// after its rotation and flips.
+func (i *Icon) forDirection(ctx context.Context) *Icon {
+	if !i.Directional || DirectionFromContext(ctx) != RTL {
+		return i
+	}
+	mirrored := i.clone()
+	mirrored.mirror = true
+	return mirrored
+}
@@ -0,0 +1,104 @@
+package templheroicons
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestDirection_FromContext(t *testing.T) {
+	ctx := context.Background()
+	if DirectionFromContext(ctx) != LTR {
+		t.Errorf("expected LTR by default")
+	}
+	if DirectionFromContext(WithDirection(ctx, RTL)) != RTL {
+		t.Errorf("expected RTL from context")
+	}
+}
+
+func TestDirection_RenderRTL(t *testing.T) {
+	arrow := &Icon{Name: "arrow-left", Size: "24", Type: "Outline", Directional: true, body: `<path d="M1 1"/>`}
+	clock := &Icon{Name: "clock", Size: "24", Type: "Outline", body: `<path d="M2 2"/>`}
+
+	tests := []struct {
+		name     string
+		icon     *Icon
+		ctx      context.Context
+		mirrored bool
+	}{
+		{"Directional icon in LTR", arrow, context.Background(), false},
+		{"Directional icon in RTL", arrow, WithDirection(context.Background(), RTL), true},
+		{"Configured directional icon in RTL", arrow.Config().SetSize(32).GetIcon(), WithDirection(context.Background(), RTL), true},
+		{"Flipped directional icon in RTL", arrow.Config().FlipH().GetIcon(), WithDirection(context.Background(), RTL), false},
+		{"Non-directional icon in RTL", clock, WithDirection(context.Background(), RTL), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var builder strings.Builder
+			if err := tt.icon.Render().Render(tt.ctx, &builder); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			// Mirroring a flipped icon flips it back
+			mirrored := strings.Count(builder.String(), `scale(-1 1)`)%2 == 1
+			if mirrored != tt.mirrored {
+				t.Errorf("Render() = %q, mirrored = %v, want %v", builder.String(), mirrored, tt.mirrored)
+			}
+		})
+	}
+
+	// Rendering in RTL does not modify the icon
+	if arrow.flipH {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestDirection_RenderRTLTransformed(t *testing.T) {
+	chevron := &Icon{Name: "chevron-right", Size: "24", Type: "Outline", Directional: true, body: `<path d="M1 1"/>`}
+	ctx := WithDirection(context.Background(), RTL)
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Rotation is mirrored as rendered",
+			builder:  chevron.Config().Rotate(90),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="translate(24 0) scale(-1 1)"><g transform="rotate(90 12 12)"><path d="M1 1"/></g></g></svg>`,
+		},
+		{
+			name:     "Badge text is not mirrored",
+			builder:  Badge(chevron, 3, WithKnockout(0)),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="translate(24 0) scale(-1 1)"><path d="M1 1"/></g><g stroke="none"><circle cx="16.8" cy="7.2" r="7.2" fill="#ef4444"/><text x="16.8" y="7.2" fill="#fff" font-family="system-ui,sans-serif" font-size="10.08" font-weight="600" text-anchor="middle" dominant-baseline="central">3</text></g></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var builder strings.Builder
+			if err := tt.builder.Render().Render(ctx, &builder); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if builder.String() != tt.expected {
+				t.Errorf("Render() = %q, want %q", builder.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestDirection_GeneratedFlags(t *testing.T) {
+	directional := []*Icon{ArrowLeft, ArrowRightMini, ChevronRight, ChevronLeftSolid, ArrowUturnLeftMicro, Backspace, BackspaceSolid}
+	for _, icon := range directional {
+		if !icon.Directional {
+			t.Errorf("expected %s to be directional", icon.Name)
+		}
+	}
+
+	nonDirectional := []*Icon{Clock, ArrowUp, ChevronDown, MagnifyingGlass, Forward, BackwardSolid}
+	for _, icon := range nonDirectional {
+		if icon.Directional {
+			t.Errorf("expected %s not to be directional", icon.Name)
+		}
+	}
+}
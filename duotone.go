@@ -0,0 +1,79 @@
+package templheroicons
+
+import (
+	"fmt"
+	"html"
+	"math"
+)
+
+// duotone holds the settings of a duotone icon.
+type duotone struct {
+	opacity float64 // Opacity of the Solid layer
+	color   string  // Resolved secondary color, empty for the primary color
+}
+
+// Duotone renders the icon in two tones: the Solid body, filled with the secondary color
+// at the given opacity (0 to 1), is layered under the Outline body drawn with the primary
+// color. Both Outline and Solid icons can be used; Mini and Micro icons, and icons without
+// an Outline/Solid counterpart in the source of the context (see WithSource), fall back
+// to a single tone in the primary color.
+// An empty secondary color uses the primary color.
+func (b *IconBuilder) Duotone(primary, secondary Color, opacity float64) *IconBuilder {
+	if opacity < 0 || opacity > 1 || math.IsNaN(opacity) {
+		b.icon.setError(fmt.Errorf("invalid duotone opacity %v", opacity))
+		return b
+	}
+	if primary != "" {
+		b.icon.Color = primary
+	}
+
+	config := &duotone{opacity: opacity}
+	if secondary != "" {
+		color, err := resolveColor(secondary.String())
+		if err != nil {
+			b.icon.setError(fmt.Errorf("invalid duotone secondary color: %w", err))
+			return b
+		}
+		config.color = color.String()
+	}
+	b.icon.duotone = config
+	return b
+}
+
+// resolveDuotone layers the Solid body of the source under the Outline body of a duotone icon.
+func (i *Icon) resolveDuotone(source Source) {
+	if i.duotone == nil || i.viewBox != 0 {
+		return
+	}
+	outlineName, ok := outlineCounterpartName(i)
+	if !ok {
+		return // Single tone fallback
+	}
+	outlineBody, outlineErr := source.Body(outlineName)
+	solidBody, solidErr := source.Body(outlineName + "-solid")
+	if outlineErr != nil || solidErr != nil {
+		return // Single tone fallback
+	}
+
+	var color string
+	if i.duotone.color != "" {
+		color = fmt.Sprintf(` color="%s"`, html.EscapeString(i.duotone.color))
+	}
+
+	i.Name = outlineName
+	i.Type = "Outline"
+	i.setBody(outlineBody)
+	i.underlay = fmt.Sprintf(`<g opacity="%s" stroke="none"%s>%s</g>`, formatCoordinate(i.duotone.opacity), color, solidBody.SVG)
+}
+
+// outlineCounterpartName returns the name of the Outline icon matching an Outline or Solid icon.
+func outlineCounterpartName(icon *Icon) (string, bool) {
+	switch icon.Type {
+	case "Outline":
+		return icon.Name, true
+	case "Solid":
+		return solidFamilyBaseName(icon)
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,115 @@
+package templheroicons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDuotone_Render(t *testing.T) {
+	SetSource(SourceFunc(func(name string) (Body, error) {
+		switch name {
+		case "cloud":
+			return Body{SVG: `<path fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/>`}, nil
+		case "cloud-solid":
+			return Body{SVG: `<path fill="currentColor" d="M2 2"/>`}, nil
+		case "cloud-20-solid", "lonely":
+			return Body{SVG: `<path fill="currentColor" d="M3 3"/>`}, nil
+		}
+		return Body{}, fmt.Errorf("icon '%s' not found", name)
+	}))
+	defer SetSource(nil)
+
+	outline := &Icon{Name: "cloud", Type: "Outline", Size: "24"}
+	solid := &Icon{Name: "cloud-solid", Type: "Solid", Size: "24"}
+	mini := &Icon{Name: "cloud-20-solid", Type: "Mini", Size: "20"}
+	lonely := &Icon{Name: "lonely", Type: "Outline", Size: "24"}
+
+	duotone := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor" color="#0369a1"><g opacity="0.2" stroke="none" color="#38bdf8"><path fill="currentColor" d="M2 2"/></g><path fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/></svg>`
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Outline icon",
+			builder:  outline.Config().Duotone("sky-700", "sky-400", 0.2),
+			expected: duotone,
+		},
+		{
+			name:     "Solid icon uses its Outline counterpart",
+			builder:  solid.Config().Duotone("sky-700", "sky-400", 0.2),
+			expected: duotone,
+		},
+		{
+			name:     "Empty secondary color uses the primary color",
+			builder:  outline.Config().Duotone("", "", 0.25),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g opacity="0.25" stroke="none"><path fill="currentColor" d="M2 2"/></g><path fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Mini icon falls back to single tone",
+			builder:  mini.Config().Duotone("sky-700", "sky-400", 0.2),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20" fill="currentColor" color="#0369a1"><path fill="currentColor" d="M3 3"/></svg>`,
+		},
+		{
+			name:     "Icon without counterpart falls back to single tone",
+			builder:  lonely.Config().Duotone("sky-700", "sky-400", 0.2),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor" color="#0369a1"><path fill="currentColor" d="M3 3"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if solid.Name != "cloud-solid" || solid.duotone != nil {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestDuotone_ContextSource(t *testing.T) {
+	icon := CloudSolid.Config().Duotone("", "", 0.5).GetIcon()
+
+	// Both tones are looked up in the source of the context
+	ctx := WithSource(context.Background(), MapSource{
+		"cloud":       {SVG: `<path d="M1 1"/>`},
+		"cloud-solid": {SVG: `<path d="M2 2"/>`},
+	})
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g opacity="0.5" stroke="none"><path d="M2 2"/></g><path d="M1 1"/></svg>`
+	if result := makeSVGTagContext(ctx, icon); result != expected {
+		t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+	}
+
+	// Without the Outline counterpart in the context source, a single tone is rendered
+	ctx = WithSource(context.Background(), MapSource{"cloud-solid": {SVG: `<path d="M2 2"/>`}})
+	expected = `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor"><path d="M2 2"/></svg>`
+	if result := makeSVGTagContext(ctx, icon); result != expected {
+		t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+	}
+}
+
+func TestDuotone_InvalidValues(t *testing.T) {
+	tests := []struct {
+		name          string
+		builder       *IconBuilder
+		expectedError string
+	}{
+		{"Invalid opacity", Cloud.Config().Duotone("", "", 1.5), "invalid duotone opacity 1.5"},
+		{"Invalid secondary color", Cloud.Config().Duotone("", "brand-500", 0.2), "invalid duotone secondary color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expectedError) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package templheroicons
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGradient_SetGradient(t *testing.T) {
+	SetSource(SourceFunc(func(name string) (Body, error) {
+		switch name {
+		case "sun":
+			return Body{SVG: `<path fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/>`}, nil
+		case "sun-solid":
+			return Body{SVG: `<path fill="currentColor" d="M2 2"/>`}, nil
+		}
+		return Body{}, fmt.Errorf("icon '%s' not found", name)
+	}))
+	defer SetSource(nil)
+
+	outline := &Icon{Name: "sun", Type: "Outline", Size: "24"}
+	solid := &Icon{Name: "sun-solid", Type: "Solid", Size: "24"}
+	stops := []GradientStop{{Offset: 0, Color: "sky-400"}, {Offset: 1, Color: "#a855f7"}}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Outline icon strokes with the gradient",
+			builder:  outline.Config().SetGradient(LinearGradient{Stops: stops}),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="url(#templheroicons-gradient-1)"><defs><linearGradient id="templheroicons-gradient-1" gradientUnits="userSpaceOnUse" x1="0" y1="12" x2="24" y2="12"><stop offset="0" stop-color="#38bdf8"/><stop offset="1" stop-color="#a855f7"/></linearGradient></defs><path fill="none" stroke="url(#templheroicons-gradient-1)" stroke-width="1.5" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Solid icon fills with the gradient",
+			builder:  solid.Config().SetGradient(LinearGradient{Stops: stops, Angle: -90}),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="url(#templheroicons-gradient-1)"><defs><linearGradient id="templheroicons-gradient-1" gradientUnits="userSpaceOnUse" x1="0" y1="12" x2="24" y2="12" gradientTransform="rotate(270 12 12)"><stop offset="0" stop-color="#38bdf8"/><stop offset="1" stop-color="#a855f7"/></linearGradient></defs><path fill="url(#templheroicons-gradient-1)" d="M2 2"/></svg>`,
+		},
+		{
+			name:     "Gradient replaces the fill override",
+			builder:  solid.Config().SetFill("red").SetGradient(LinearGradient{Stops: stops}),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="url(#templheroicons-gradient-1)"><defs><linearGradient id="templheroicons-gradient-1" gradientUnits="userSpaceOnUse" x1="0" y1="12" x2="24" y2="12"><stop offset="0" stop-color="#38bdf8"/><stop offset="1" stop-color="#a855f7"/></linearGradient></defs><path fill="url(#templheroicons-gradient-1)" d="M2 2"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTagContext(WithIDScope(context.Background()), tt.builder.GetIcon())
+			if result != tt.expected {
+				t.Errorf("makeSVGTagContext() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGradient_SetGradientErrors(t *testing.T) {
+	icon := &Icon{Name: "sun", Type: "Outline", Size: "24", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name     string
+		gradient LinearGradient
+		expected string
+	}{
+		{"Single stop", LinearGradient{Stops: []GradientStop{{0, "red"}}}, "invalid gradient: at least 2 stops are required"},
+		{"Offset out of range", LinearGradient{Stops: []GradientStop{{0, "red"}, {1.5, "blue"}}}, "invalid gradient stop offset 1.5"},
+		{"Unknown color", LinearGradient{Stops: []GradientStop{{0, "red"}, {1, "nope-500"}}}, "invalid gradient stop: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(icon.Config().SetGradient(tt.gradient).GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expected) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGradient_UniqueIDs(t *testing.T) {
+	icon := (&Icon{Name: "sun", Type: "Solid", Size: "24", body: `<path d="M1 1"/>`}).Config().
+		SetGradient(LinearGradient{Stops: []GradientStop{{0, "red"}, {1, "blue"}}}).
+		GetIcon()
+
+	// Icons rendered on the same page get distinct IDs
+	ctx := WithIDScope(context.Background())
+	var page strings.Builder
+	for range 2 {
+		if err := icon.Render().Render(ctx, &page); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+	}
+	for _, id := range []string{`id="templheroicons-gradient-1"`, `id="templheroicons-gradient-2"`} {
+		if strings.Count(page.String(), id) != 1 {
+			t.Errorf("Render() = %q, want %s once", page.String(), id)
+		}
+	}
+
+	// Each page numbers its IDs from 1
+	var other strings.Builder
+	if err := icon.Render().Render(WithIDScope(context.Background()), &other); err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.Contains(other.String(), `id="templheroicons-gradient-1"`) {
+		t.Errorf("Render() = %q, want the first ID of the page", other.String())
+	}
+
+	// Without a scope, IDs are unique process-wide
+	first, second := makeSVGTag(icon), makeSVGTag(icon)
+	if first == second {
+		t.Errorf("makeSVGTag() rendered the same IDs twice: %q", first)
+	}
+
+	// Process-wide IDs never collide with the IDs of a scoped page
+	if !strings.Contains(first, `id="templheroicons-global-gradient-`) || strings.Contains(first, `id="templheroicons-gradient-`) {
+		t.Errorf("makeSVGTag() = %q, want a process-wide ID", first)
+	}
+}
@@ -2,17 +2,21 @@ package templheroicons
 
 import (
 	"fmt"
-	"html"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/a-h/templ"
 )
 
+// errorSVGComment renders an error as an HTML comment.
+// Angle brackets are escaped so the message cannot close the comment early.
 func errorSVGComment(err error) string {
-	return fmt.Sprintf("<!-- Error: %s -->", err)
+	return fmt.Sprintf("<!-- Error: %s -->", commentEscaper.Replace(err.Error()))
 }
 
+var commentEscaper = strings.NewReplacer("<", "&lt;", ">", "&gt;")
+
 func getViewBoxDimensions(iconType string) string {
 	switch iconType {
 	case "Mini":
@@ -24,20 +28,65 @@ func getViewBoxDimensions(iconType string) string {
 	}
 }
 
-func getTypeAttributes(iconType string) string {
-	// Map of type attributes for each icon type
-	attributesMap := map[string]string{
-		"Outline": ` fill="none" stroke-width="1.5" stroke="currentColor"`,
-		"Solid":   ` fill="currentColor"`,
-		"Micro":   ` fill="currentColor"`,
-		"Mini":    ` fill="currentColor"`,
+// getViewBoxSize returns the size of the square viewBox of the icon type.
+func getViewBoxSize(iconType string) float64 {
+	size, _ := strconv.ParseFloat(getViewBoxDimensions(iconType), 64)
+	return size
+}
+
+// getDimensions returns the validated width and height of the icon,
+// using the width and height overrides when set. Icons without size
+// (e.g., &Icon{Name: "moon"} literals) use the size of their viewBox.
+func getDimensions(icon *Icon) (Size, Size, error) {
+	size := icon.Size
+	if size == "" {
+		size = Size(formatCoordinate(icon.viewBoxSize()))
+	}
+	width, height := size, size
+	if icon.width != "" {
+		width = icon.width
+	}
+	if icon.height != "" {
+		height = icon.height
 	}
 
-	// Return attributes for the specific type, or an empty string if not found
-	if attrs, exists := attributesMap[iconType]; exists {
-		return attrs
+	width, err := ParseSize(width.String())
+	if err != nil {
+		return "", "", err
 	}
-	return ""
+	height, err = ParseSize(height.String())
+	if err != nil {
+		return "", "", err
+	}
+	return width, height, nil
+}
+
+// typeAttributesMap holds the default presentation attributes for each icon type.
+var typeAttributesMap = map[string][]presentationAttribute{
+	"Outline": {{"fill", "none"}, {"stroke-width", "1.5"}, {"stroke", "currentColor"}},
+	"Solid":   {{"fill", "currentColor"}},
+	"Micro":   {{"fill", "currentColor"}},
+	"Mini":    {{"fill", "currentColor"}},
+}
+
+// getTypeAttributes returns the presentation attributes for the icon type.
+// Overrides replace the type defaults, or are appended when the type has no default.
+func getTypeAttributes(iconType string, overrides ...presentationAttribute) string {
+	attrs := typeAttributesMap[iconType] // Empty for unknown types
+
+	var builder strings.Builder
+	for _, attr := range attrs {
+		if override, ok := findPresentationAttribute(overrides, attr.name); ok {
+			attr = override
+		}
+		fmt.Fprintf(&builder, ` %s="%s"`, attr.name, attr.value)
+	}
+	for _, override := range overrides {
+		if _, ok := findPresentationAttribute(attrs, override.name); !ok {
+			fmt.Fprintf(&builder, ` %s="%s"`, override.name, override.value)
+		}
+	}
+	return builder.String()
 }
 
 // Reserved attributes for SVG tags that should not be overwritten.
@@ -51,55 +100,81 @@ var reservedSVGAttributes = map[string]struct{}{
 	"fill":         {},
 }
 
-// sanitizeAttribute ensures that attribute keys and values are safe for inclusion in the SVG tag.
-func sanitizeAttribute(key, value string) (string, string, bool) {
-	// Define allowlist for event attributes
-	allowedEventAttributes := map[string]struct{}{
-		"onclick":  {},
-		"onchange": {},
-		"onhover":  {},
+// isReservedAttribute reports whether the key matches a reserved attribute, ignoring case
+// since HTML parsers lowercase attribute names.
+func isReservedAttribute(key string) bool {
+	for reserved := range reservedSVGAttributes {
+		if strings.EqualFold(key, reserved) {
+			return true
+		}
 	}
+	return false
+}
 
-	// Check for unsafe attributes
-	if _, isEvent := allowedEventAttributes[key]; isEvent {
-		// For event attributes, only allow simple JS functions (no <script> tags, eval, etc.)
-		if strings.Contains(strings.ToLower(value), "<script>") || strings.Contains(strings.ToLower(value), "javascript:") {
-			return "", "", false // Unsafe value
-		}
+// sortedAttributeKeys returns the attribute keys sorted for deterministic order.
+func sortedAttributeKeys(attrs templ.Attributes) []string {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
+}
 
-	// Escape any unsafe characters for all attributes
-	escapedKey := html.EscapeString(key)
-	escapedValue := html.EscapeString(value)
+// collectScripts returns the templ script handlers bound to event attributes, in attribute order.
+func collectScripts(attrs templ.Attributes) []templ.ComponentScript {
+	var scripts []templ.ComponentScript
+	for _, key := range sortedAttributeKeys(attrs) {
+		if script, ok := attrs[key].(templ.ComponentScript); ok && isEventAttribute(key) {
+			scripts = append(scripts, script)
+		}
+	}
+	return scripts
+}
 
-	return escapedKey, escapedValue, true // Safe attribute
+// withClass returns a copy of the attributes with the class appended to the `class` attribute.
+func withClass(attrs templ.Attributes, class string) templ.Attributes {
+	if class == "" {
+		return attrs
+	}
+	merged := make(templ.Attributes, len(attrs)+1)
+	for k, v := range attrs {
+		merged[k] = v
+	}
+	if existing, ok := merged["class"].(string); ok && existing != "" {
+		class = existing + " " + class
+	}
+	merged["class"] = class
+	return merged
 }
 
 // addAttributesToSVG adds templ.Attributes to the SVG tag, placing them at the end of the <svg> opening tag.
 // Reserved attributes are skipped to avoid overwriting critical SVG settings.
-// Attributes are sanitized to prevent XSS or injection attacks.
+// Attributes are sanitized according to the AttributePolicy to prevent XSS or injection attacks.
 func addAttributesToSVG(builder *strings.Builder, attrs templ.Attributes) {
 	if len(attrs) == 0 {
 		return
 	}
 
-	// Extract keys and sort them for deterministic order
-	keys := make([]string, 0, len(attrs))
-	for key := range attrs {
-		keys = append(keys, key)
-	}
-	sort.Strings(keys)
+	// Process attributes in sorted order for deterministic output
+	for _, key := range sortedAttributeKeys(attrs) {
+		// Skip reserved attributes
+		if isReservedAttribute(key) {
+			continue
+		}
 
-	// Process attributes in sorted order
-	for _, key := range keys {
-		value, ok := attrs[key].(string) // Ensure value is a string
-		if !ok {
-			// Skip attributes with non-string values
+		// templ script handlers reference the function rendered by Icon.Render.
+		// The call is already escaped by templ, as it does for its own attributes.
+		if script, ok := attrs[key].(templ.ComponentScript); ok {
+			if isEventAttribute(key) && isValidAttributeName(key) {
+				fmt.Fprintf(builder, ` %s="%s"`, key, script.Call)
+			}
 			continue
 		}
 
-		// Skip reserved attributes
-		if _, isReserved := reservedSVGAttributes[key]; isReserved {
+		value, ok := attrs[key].(string) // Ensure value is a string
+		if !ok {
+			// Skip attributes with non-string values
 			continue
 		}
 
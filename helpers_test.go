@@ -70,6 +70,20 @@ func TestHelpers_addAttributesToSVG(t *testing.T) {
 			},
 			expected: ` aria-hidden="true"`, // Unsafe "onclick" is excluded
 		},
+		{
+			name: "templ script handler on event attribute is allowed",
+			attrs: templ.Attributes{
+				"onclick": templ.ComponentScript{Name: "__templ_toggle_1234", Call: "__templ_toggle_1234(&#34;menu&#34;)"},
+			},
+			expected: ` onclick="__templ_toggle_1234(&#34;menu&#34;)"`,
+		},
+		{
+			name: "templ script handler on non-event attribute is skipped",
+			attrs: templ.Attributes{
+				"class": templ.ComponentScript{Name: "__templ_toggle_1234", Call: "__templ_toggle_1234()"},
+			},
+			expected: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,3 +101,58 @@ func TestHelpers_addAttributesToSVG(t *testing.T) {
 		})
 	}
 }
+
+func TestHelpers_collectScripts(t *testing.T) {
+	click := templ.ComponentScript{Name: "__templ_click_1", Function: "function __templ_click_1(){}", Call: "__templ_click_1()"}
+	change := templ.ComponentScript{Name: "__templ_change_2", Function: "function __templ_change_2(){}", Call: "__templ_change_2()"}
+
+	scripts := collectScripts(templ.Attributes{
+		"onclick":  click,
+		"onchange": change,
+		"class":    click, // Not an event attribute
+		"title":    "plain",
+	})
+
+	if len(scripts) != 2 {
+		t.Fatalf("collectScripts() returned %d scripts, want 2", len(scripts))
+	}
+	if scripts[0].Name != change.Name || scripts[1].Name != click.Name {
+		t.Errorf("collectScripts() = [%s %s], want [%s %s]", scripts[0].Name, scripts[1].Name, change.Name, click.Name)
+	}
+}
+
+func TestHelpers_getDimensionsWithoutSize(t *testing.T) {
+	custom := NewIcon("logo", "Solid", 32, `<path d="M1 1"/>`)
+	custom.Size = ""
+
+	tests := []struct {
+		name           string
+		icon           *Icon
+		expectedWidth  Size
+		expectedHeight Size
+	}{
+		{"Outline literal", &Icon{Name: "moon", Type: "Outline"}, "24", "24"},
+		{"Mini literal", &Icon{Name: "moon-20-solid", Type: "Mini"}, "20", "20"},
+		{"Micro literal", &Icon{Name: "moon-16-solid", Type: "Micro"}, "16", "16"},
+		{"NewIcon without size", custom, "32", "32"},
+		{"Width override without size", &Icon{Name: "moon", Type: "Outline", width: "2rem"}, "2rem", "24"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height, err := getDimensions(tt.icon)
+			if err != nil {
+				t.Fatalf("getDimensions() error = %v", err)
+			}
+			if width != tt.expectedWidth || height != tt.expectedHeight {
+				t.Errorf("getDimensions() = %s x %s, want %s x %s", width, height, tt.expectedWidth, tt.expectedHeight)
+			}
+		})
+	}
+
+	// Icons without size render instead of an error comment
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="32" height="32" viewBox="0 0 32 32" fill="currentColor"><path d="M1 1"/></svg>`
+	if result := makeSVGTag(custom); result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+}
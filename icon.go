@@ -1,44 +1,89 @@
 package templheroicons
 
 import (
+	"context"
 	_ "embed"
 	"fmt"
+	"html"
 	"io"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/a-h/templ"
-	"github.com/tidwall/gjson"
 )
 
 // Cache to store parsed icon body content for reuse
 var (
-	iconBodyCache = map[string]string{}
-	cacheMutex    sync.Mutex
+	iconBodyCache    = map[string]Body{}
+	iconBodiesLoaded bool // The embedded dataset was parsed into iconBodyCache
+	cacheMutex       sync.Mutex
 )
 
-// Size represents the size of UI components.
-type Size string
-
-// String returns the string representation of a Size.
-func (s Size) String() string {
-	return string(s)
-}
-
 // Icon represents a single icon with its attributes.
 type Icon struct {
 	Name  string           `json:"name"` // Name of the icon (e.g., "moon")
 	Type  string           `json:"type"` // Type of the icon (e.g., "Outline", "Solid")
 	Size  Size             `json:"size"` // Size of the icon (e.g., "24", "48")
-	Color string           // Optional color for the icon's fill
+	Color Color            // Optional color for the icon's fill
 	Attrs templ.Attributes // Custom attributes to be added to the <svg> tag
 	body  string           // Cached body of the icon's SVG path (immutable)
+
+	Directional bool `json:"directional"` // Whether the icon is mirrored in RTL (e.g., arrows, chevrons)
+
+	viewBox float64 // Size of the square viewBox of icons created with NewIcon, 0 for the type default
+
+	width    Size // Optional width overriding Size
+	height   Size // Optional height overriding Size
+	omitSize bool // Omit width and height, leaving sizing to CSS
+
+	fill        Color   // Optional fill paint overriding the type default
+	stroke      Color   // Optional stroke paint overriding the type default
+	strokeWidth float64 // Optional stroke width overriding the type default
+
+	absoluteStrokeWidth bool // Keep the stroke width constant in pixels at any size
+
+	rotate float64 // Clockwise rotation in degrees, in the [0, 360) range
+	flipH  bool    // Flip horizontally
+	flipV  bool    // Flip vertically
+	mirror bool    // Mirror for a right-to-left context, over the other transforms
+
+	animation         Animation     // Optional animation preset
+	animationDuration time.Duration // Duration of one animation cycle
+
+	layers   string // Markup composed over the body (e.g., Stack overlays)
+	underlay string // Markup composed under the body (e.g., Duotone Solid layer)
+	labels   string // Markup composed over the transformed icon, kept upright (e.g., Badge bubbles)
+
+	knockouts []knockout // Holes cut out of the body around overlays, masked when rendering
+
+	autoSize     int           // Size set with SetSizeAuto, picking the closest variant when rendering
+	duotone      *duotone      // Optional duotone rendering, resolved when rendering
+	compositions []composition // Stack overlays and Badge bubbles, composed when rendering
+
+	container *container      // Optional background shape rendered behind the icon
+	gradient  *LinearGradient // Optional gradient painting the icon
+
+	err error // First configuration error, rendered as an error comment
 }
 
 // Render generates the complete SVG tag for the icon.
+// Script handlers set with SetAttrs (templ.ComponentScript values) are emitted
+// once per render through templ, using the nonce set with templ.WithNonce.
+// Directional icons are mirrored when the context direction is RTL (see WithDirection).
 func (i *Icon) Render() templ.Component {
-	return templ.Raw(makeSVGTag(i))
+	return templ.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		// Emit the script definitions and animation styles before the <svg> tag referencing them
+		if err := templ.RenderScriptItems(ctx, w, collectScripts(i.Attrs)...); err != nil {
+			return err
+		}
+		if err := renderAnimationStyle(ctx, w, i); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, makeSVGTagContext(ctx, i.forDirection(ctx)))
+		return err
+	})
 }
 
 // IconBuilder is a builder for configuring an Icon.
@@ -54,6 +99,18 @@ func (icon *Icon) Config() *IconBuilder {
 	}
 }
 
+// NewIcon creates an icon from its SVG body and the size of its square viewBox,
+// for icon sets generated from other Iconify datasets (see `icons-maker -prefix`).
+func NewIcon(name, iconType string, viewBox int, body string) *Icon {
+	return &Icon{
+		Name:    name,
+		Type:    iconType,
+		Size:    Size(strconv.Itoa(viewBox)),
+		body:    body,
+		viewBox: float64(viewBox),
+	}
+}
+
 // ConfigureIcon creates a new builder from an existing icon.
 func ConfigureIcon(icon *Icon) *IconBuilder {
 	return &IconBuilder{
@@ -67,9 +124,41 @@ func (b *IconBuilder) SetSize(size int) *IconBuilder {
 	return b
 }
 
-// SetColor sets the fill color of the icon.
+// SetSizeValue sets the size of the icon with units (e.g., SizeRem(1.5), SizeEm(1)).
+func (b *IconBuilder) SetSizeValue(size Size) *IconBuilder {
+	b.icon.Size = size
+	return b
+}
+
+// SetWidth overrides the width of the icon, keeping its height.
+func (b *IconBuilder) SetWidth(width Size) *IconBuilder {
+	b.icon.width = width
+	return b
+}
+
+// SetHeight overrides the height of the icon, keeping its width.
+func (b *IconBuilder) SetHeight(height Size) *IconBuilder {
+	b.icon.height = height
+	return b
+}
+
+// OmitSize omits the width and height attributes, so that CSS classes (e.g., `size-6`) control sizing.
+func (b *IconBuilder) OmitSize() *IconBuilder {
+	b.icon.omitSize = true
+	return b
+}
+
+// SetColor sets the fill color of the icon from a CSS color string or a color token
+// known by the ColorResolver (e.g., "cyan-400"). The value is validated when rendering:
+// an invalid color or unknown token renders an error comment.
 func (b *IconBuilder) SetColor(value string) *IconBuilder {
-	b.icon.Color = value
+	b.icon.Color = Color(value)
+	return b
+}
+
+// SetColorValue sets the fill color of the icon (e.g., Hex("#22d3ee"), Var("--brand")).
+func (b *IconBuilder) SetColorValue(color Color) *IconBuilder {
+	b.icon.Color = color
 	return b
 }
 
@@ -103,97 +192,188 @@ func (i *Icon) clone() *Icon {
 		Color: i.Color,
 		Attrs: attrsCopy, // Use the deep copy of the attributes
 		body:  i.body,    // The body is shared since it's immutable
+
+		Directional: i.Directional,
+
+		viewBox: i.viewBox,
+
+		width:    i.width,
+		height:   i.height,
+		omitSize: i.omitSize,
+
+		fill:        i.fill,
+		stroke:      i.stroke,
+		strokeWidth: i.strokeWidth,
+
+		absoluteStrokeWidth: i.absoluteStrokeWidth,
+
+		rotate: i.rotate,
+		flipH:  i.flipH,
+		flipV:  i.flipV,
+		mirror: i.mirror,
+
+		animation:         i.animation,
+		animationDuration: i.animationDuration,
+
+		layers:   i.layers,
+		underlay: i.underlay,
+		labels:   i.labels,
+
+		knockouts: append([]knockout(nil), i.knockouts...),
+
+		autoSize:     i.autoSize,
+		duotone:      i.duotone, // The duotone is shared since it's immutable
+		compositions: append([]composition(nil), i.compositions...),
+
+		container: i.container, // The container is shared since it's immutable
+		gradient:  i.gradient,  // The gradient is shared since it's immutable
+
+		err: i.err,
 	}
 }
 
-// fetchBody ensures that the body of the icon is loaded from the cache or file.
-func (i *Icon) fetchBody() error {
-	if i.body != "" {
-		return nil // Body is already cached
+// viewBoxSize returns the size of the square viewBox of the icon.
+func (i *Icon) viewBoxSize() float64 {
+	if i.viewBox != 0 {
+		return i.viewBox
 	}
+	return getViewBoxSize(i.Type)
+}
 
-	body, err := getIconBody(i.Name)
-	if err != nil {
-		return err
+// setError records a configuration error, keeping the first one.
+func (i *Icon) setError(err error) {
+	if i.err == nil {
+		i.err = err
+	}
+}
+
+// withBody returns the icon with its body, looked up in the source of the context if unset,
+// and its variants and compositions resolved. The icon is copied rather than modified,
+// since the source may differ between renders.
+func (i *Icon) withBody(ctx context.Context) (*Icon, error) {
+	return i.resolve(SourceFromContext(ctx))
+}
+
+// resolve returns a copy of the icon with its body, variants and compositions looked up
+// in the source, or the icon itself when there is nothing to look up.
+func (i *Icon) resolve(source Source) (*Icon, error) {
+	if i.body != "" && i.autoSize == 0 && i.duotone == nil && len(i.compositions) == 0 {
+		return i, nil
 	}
 
-	i.body = body
-	return nil
+	resolved := *i
+	resolved.knockouts = append([]knockout(nil), i.knockouts...)
+	resolved.resolveVariant(source)
+	resolved.resolveDuotone(source)
+	if resolved.body == "" {
+		body, err := source.Body(resolved.Name)
+		if err != nil {
+			return nil, err
+		}
+		resolved.setBody(body)
+	}
+	for _, compose := range i.compositions {
+		if err := compose(&resolved, source); err != nil {
+			return nil, err
+		}
+	}
+	return &resolved, nil
+}
+
+// setBody sets the body of the icon, with its viewBox when it differs from the type default.
+func (i *Icon) setBody(body Body) {
+	i.body = body.SVG
+	if i.viewBox == 0 && body.ViewBox > 0 && float64(body.ViewBox) != getViewBoxSize(i.Type) {
+		i.viewBox = float64(body.ViewBox)
+	}
 }
 
 // makeSVGTag generates the full SVG tag for the icon.
 func makeSVGTag(icon *Icon) string {
+	return makeSVGTagContext(context.Background(), icon)
+}
+
+// makeSVGTagContext generates the full SVG tag for the icon, numbering its definition IDs
+// (e.g., gradients) with the context.
+func makeSVGTagContext(ctx context.Context, icon *Icon) string {
+	// Report configuration errors instead of rendering an unexpected icon
+	if icon.err != nil {
+		return errorSVGComment(icon.err)
+	}
+
 	// Ensure the body is loaded before rendering
-	if err := icon.fetchBody(); err != nil {
+	icon, err := icon.withBody(ctx)
+	if err != nil {
+		return errorSVGComment(err)
+	}
+
+	// Validate the fill and stroke overrides
+	overrides, err := getPresentationOverrides(icon)
+	if err != nil {
 		return errorSVGComment(err)
 	}
+	nonScalingStroke := false
+	if icon.absoluteStrokeWidth {
+		overrides, nonScalingStroke = applyAbsoluteStrokeWidth(icon, overrides)
+	}
+	overrides, gradientDefs := applyGradient(ctx, icon, overrides)
 
 	// Determine the appropriate viewBox and type-based attributes
-	viewBox := getViewBoxDimensions(icon.Type)
-	typeAttributes := getTypeAttributes(icon.Type)
+	viewBox := formatCoordinate(getViewBoxExtent(icon))
+	typeAttributes := getTypeAttributes(icon.Type, overrides...)
 
 	var builder strings.Builder
 	// Construct the opening <svg> tag with common attributes
-	fmt.Fprintf(&builder, `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]s" height="%[1]s" viewBox="0 0 %[2]s %[2]s"%s`,
-		icon.Size.String(),
-		viewBox,
-		typeAttributes,
-	)
+	builder.WriteString(`<svg xmlns="http://www.w3.org/2000/svg"`)
+	if !icon.omitSize {
+		width, height, err := getDimensions(icon)
+		if err != nil {
+			return errorSVGComment(err)
+		}
+		if isQuarterTurn(icon.rotate) {
+			width, height = height, width
+		}
+		fmt.Fprintf(&builder, ` width="%s" height="%s"`, width, height)
+	}
+	fmt.Fprintf(&builder, ` viewBox="0 0 %[1]s %[1]s"%s`, viewBox, typeAttributes)
 
-	// If a custom color is set, add it to the <svg> tag
+	// If a custom color is set, validate it and add it to the <svg> tag
 	if icon.Color != "" {
-		fmt.Fprintf(&builder, ` color="%s"`, icon.Color)
+		color, err := resolveColor(icon.Color.String())
+		if err != nil {
+			return errorSVGComment(err)
+		}
+		fmt.Fprintf(&builder, ` color="%s"`, html.EscapeString(color.String()))
 	}
 
-	// Add user-defined attributes to the <svg> tag
-	addAttributesToSVG(&builder, icon.Attrs)
+	// Add user-defined attributes to the <svg> tag, with the animation class
+	addAttributesToSVG(&builder, withClass(icon.Attrs, animationClass(icon)))
 
 	// Close the opening <svg> tag, add the body, and close the <svg> tag
 	builder.WriteString(">")
-	builder.WriteString(icon.body)
-	builder.WriteString(`</svg>`)
-
-	return builder.String()
-}
-
-// getIconBody retrieves the body of an icon by its name, with thread-safe caching.
-var getIconBody = func(name string) (string, error) {
-	cacheMutex.Lock()
-	defer cacheMutex.Unlock()
-
-	// Check if the body is already cached
-	if body, found := iconBodyCache[name]; found {
-		return body, nil
+	body := applyPresentationOverrides(icon.body, overrides)
+	if nonScalingStroke {
+		body = applyNonScalingStroke(body)
 	}
-
-	// Read and parse the JSON file containing icon data
-	jsonFilename := "data/heroicons_cache.json"
-	heroiconsData, _ := heroiconsJSONSource.Open(jsonFilename)
-	defer heroiconsData.Close()
-
-	data, _ := io.ReadAll(heroiconsData)
-
-	// Check if the JSON data is valid
-	if !gjson.ValidBytes(data) {
-		return "", fmt.Errorf("failed to parse heroicons JSON")
+	// The knockouts cut the overlays out of the duotone underlay too
+	body, maskDefs := applyKnockouts(ctx, icon, icon.underlay+body, false)
+	if defs := maskDefs + gradientDefs; defs != "" {
+		body = "<defs>" + defs + "</defs>" + body
 	}
-
-	// Extract the "icons" key from the JSON data
-	iconsResult := gjson.GetBytes(data, "icons")
-
-	// If the "icons" key exists, populate the cache
-	if iconsResult.Exists() {
-		iconsResult.ForEach(func(key, value gjson.Result) bool {
-			iconBody := value.Get("body").String()
-			iconBodyCache[key.String()] = iconBody
-			return true
-		})
+	body = wrapTransform(body+icon.layers, getTransform(icon))
+	body = wrapTransform(body, getMirrorTransform(icon))
+	// Labels are drawn over the transformed icon, so that their text is never flipped
+	body, maskDefs = applyKnockouts(ctx, icon, body, true)
+	if maskDefs != "" {
+		body = "<defs>" + maskDefs + "</defs>" + body
 	}
-
-	// Return the requested icon body from the cache
-	body, exists := iconBodyCache[name]
-	if !exists {
-		return "", fmt.Errorf("icon '%s' not found", name)
+	body += icon.labels
+	body, err = wrapContainer(icon, body)
+	if err != nil {
+		return errorSVGComment(err)
 	}
-	return body, nil
+	builder.WriteString(body)
+	builder.WriteString(`</svg>`)
+
+	return builder.String()
 }
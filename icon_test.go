@@ -1,6 +1,8 @@
 package templheroicons
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -122,24 +124,18 @@ func TestIcon_default(t *testing.T) {
 }
 
 func TestIcon_makeSVGTag(t *testing.T) {
-	// Save the original implementation
-	originalGetIconBody := getIconBody
-
-	// Defer the restoration of the original function
-	defer func() { getIconBody = originalGetIconBody }()
-
-	// Mock `getIconBody` to return different responses
-	mockGetIconBody := func(name string) (string, error) {
+	// Mock the source to return different responses
+	SetSource(SourceFunc(func(name string) (Body, error) {
 		switch name {
 		case "existing-icon":
-			return `<path d="M10 20a10 10 0 1 0 0-20 10 10 0 0 0 0 20z"/>`, nil
+			return Body{SVG: `<path d="M10 20a10 10 0 1 0 0-20 10 10 0 0 0 0 20z"/>`}, nil
 		case "error-icon":
-			return "", fmt.Errorf("icon '%s' not found", name)
+			return Body{}, fmt.Errorf("icon '%s' not found", name)
 		default:
-			return "", fmt.Errorf("icon '%s' not found", name)
+			return Body{}, fmt.Errorf("icon '%s' not found", name)
 		}
-	}
-	getIconBody = mockGetIconBody
+	}))
+	defer SetSource(nil)
 
 	tests := []struct {
 		name           string
@@ -147,7 +143,7 @@ func TestIcon_makeSVGTag(t *testing.T) {
 		expectedOutput string
 	}{
 		{
-			name: "Body already set, should not call getIconBody",
+			name: "Body already set, should not look up the source",
 			icon: &Icon{
 				Name: "existing-icon",
 				Size: "24",
@@ -157,7 +153,7 @@ func TestIcon_makeSVGTag(t *testing.T) {
 			expectedOutput: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M12 2a10 10 0 1 0 0 20a10 10 0 0 0 0-20z"/></svg>`,
 		},
 		{
-			name: "Body not set, getIconBody returns successfully",
+			name: "Body not set, the source returns successfully",
 			icon: &Icon{
 				Name: "existing-icon",
 				Size: "24",
@@ -166,7 +162,7 @@ func TestIcon_makeSVGTag(t *testing.T) {
 			expectedOutput: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M10 20a10 10 0 1 0 0-20 10 10 0 0 0 0 20z"/></svg>`,
 		},
 		{
-			name: "Body not set, getIconBody returns an error",
+			name: "Body not set, the source returns an error",
 			icon: &Icon{
 				Name: "error-icon",
 				Size: "24",
@@ -377,10 +373,76 @@ func TestIcon_SetSizeIsolation(t *testing.T) {
 	}
 }
 
+func TestIcon_RenderScriptHandler(t *testing.T) {
+	script := templ.ComponentScript{
+		Name:     "__templ_toggle_abcd",
+		Function: "function __templ_toggle_abcd(){}",
+		Call:     "__templ_toggle_abcd()",
+	}
+	icon := &Icon{
+		Name: "test-icon",
+		Size: "24",
+		Type: "Solid",
+		body: `<path d="M1 1"/>`,
+	}
+	builder := icon.Config().SetAttrs(templ.Attributes{"onclick": script})
+
+	ctx := templ.WithNonce(templ.InitializeContext(context.Background()), "r4nd0m")
+
+	var buf bytes.Buffer
+	// Render twice in the same context: the script must be emitted only once
+	for range 2 {
+		if err := builder.Render().Render(ctx, &buf); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	svg := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor" onclick="__templ_toggle_abcd()"><path d="M1 1"/></svg>`
+	expected := `<script nonce="r4nd0m">function __templ_toggle_abcd(){}</script>` + svg + svg
+	if buf.String() != expected {
+		t.Errorf("Render() = %q, want %q", buf.String(), expected)
+	}
+}
+
+func TestIcon_NewIcon(t *testing.T) {
+	icon := NewIcon("camera", "Outline", 32, `<path d="M1 1"/>`)
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Default size is the viewBox size",
+			builder:  icon.Config(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="32" height="32" viewBox="0 0 32 32" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Transforms use the viewBox size",
+			builder:  icon.Config().SetSize(16).FlipH(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" viewBox="0 0 32 32" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="translate(32 0) scale(-1 1)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "No heroicons variants",
+			builder:  icon.Config().SetSizeAuto(20).Duotone("", "", 0.2),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 32 32" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
 // 2. Tests for JSON-Based Functionality
 // These tests cover JSON parsing, caching, and error handling.
 
-func TestIcon_getIconBody_RealData(t *testing.T) {
+func TestIcon_currentSource_RealData(t *testing.T) {
 	tests := []struct {
 		name           string
 		iconName       string
@@ -415,7 +477,7 @@ func TestIcon_getIconBody_RealData(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			body, err := getIconBody(tt.iconName)
+			body, err := currentSource().Body(tt.iconName)
 
 			if tt.expectingError {
 				if err == nil {
@@ -425,23 +487,23 @@ func TestIcon_getIconBody_RealData(t *testing.T) {
 				if err != nil {
 					t.Errorf("unexpected error: %v", err)
 				}
-				if body != tt.expectedBody {
-					t.Errorf("getIconBody() = %q, want %q", body, tt.expectedBody)
+				if body.SVG != tt.expectedBody {
+					t.Errorf("currentSource().Body() = %q, want %q", body.SVG, tt.expectedBody)
 				}
 			}
 		})
 	}
 }
 
-func TestIcon_getIconBody_OnceWithRealData(t *testing.T) {
+func TestIcon_currentSource_OnceWithRealData(t *testing.T) {
 	// First call should initialize the data
-	_, err := getIconBody("academic-cap")
+	_, err := currentSource().Body("academic-cap")
 	if err != nil {
 		t.Fatalf("unexpected error during first call: %v", err)
 	}
 
 	// Ensure no error on subsequent calls for valid icons
-	_, err = getIconBody("academic-cap-solid")
+	_, err = currentSource().Body("academic-cap-solid")
 	if err != nil {
 		t.Fatalf("unexpected error during subsequent call: %v", err)
 	}
@@ -462,6 +524,7 @@ func TestIcon_fetchBody(t *testing.T) {
 	heroiconsJSONSource = mockInvalidJSONFS(validJSON)
 	defer func() {
 		heroiconsJSONSource = heroiconsJSON // Restore original embedded JSON
+		resetTestState()
 	}()
 
 	t.Run("Fetches and caches body", func(t *testing.T) {
@@ -482,7 +545,7 @@ func TestIcon_fetchBody(t *testing.T) {
 	})
 }
 
-func TestIcon_getIconBody_JSONParsing(t *testing.T) {
+func TestIcon_currentSource_JSONParsing(t *testing.T) {
 	tests := []struct {
 		name           string
 		mockJSON       string
@@ -525,9 +588,10 @@ func TestIcon_getIconBody_JSONParsing(t *testing.T) {
 			heroiconsJSONSource = mockInvalidJSONFS(tt.mockJSON)
 			defer func() {
 				heroiconsJSONSource = heroiconsJSON // Restore original embedded FS
+				resetTestState()
 			}()
 
-			result, err := getIconBody(tt.iconName)
+			result, err := currentSource().Body(tt.iconName)
 
 			if tt.expectedError != "" {
 				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
@@ -535,8 +599,8 @@ func TestIcon_getIconBody_JSONParsing(t *testing.T) {
 				}
 			} else if err != nil {
 				t.Errorf("Unexpected error: %v", err)
-			} else if result != tt.expectedResult {
-				t.Errorf("Expected result %q, got %q", tt.expectedResult, result)
+			} else if result.SVG != tt.expectedResult {
+				t.Errorf("Expected result %q, got %q", tt.expectedResult, result.SVG)
 			}
 		})
 	}
@@ -580,7 +644,9 @@ func (f *mockFile) Stat() (fs.FileInfo, error) {
 }
 
 func resetTestState() {
-	iconBodyCache = map[string]string{}
+	iconBodyCache = map[string]Body{}
+	iconBodiesLoaded = false
+	registeredIcons = map[string]registeredIcon{}
 }
 
 func TestMockFS(t *testing.T) {
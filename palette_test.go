@@ -0,0 +1,89 @@
+package templheroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPalette_TailwindPalette(t *testing.T) {
+	tests := map[string]Color{
+		"cyan-400":    "#22d3ee",
+		"teal-400":    "#2dd4bf",
+		"neutral-900": "#171717",
+		"slate-50":    "#f8fafc",
+		"rose-950":    "#4c0519",
+		"white":       "#fff",
+	}
+	for token, expected := range tests {
+		if color, ok := TailwindPalette.ResolveColor(token); !ok || color != expected {
+			t.Errorf("TailwindPalette.ResolveColor(%q) = %q, %v; want %q", token, color, ok, expected)
+		}
+	}
+
+	// 22 colors with 11 shades each, plus black and white
+	if len(TailwindPalette) != 22*11+2 {
+		t.Errorf("expected %d palette entries, got %d", 22*11+2, len(TailwindPalette))
+	}
+	for token, color := range TailwindPalette {
+		if _, err := ParseColor(color.String()); err != nil {
+			t.Errorf("palette color %q for %q is invalid: %v", color, token, err)
+		}
+	}
+}
+
+func TestPalette_resolveColor(t *testing.T) {
+	defer SetColorResolver(TailwindPalette)
+
+	brand := ColorPalette{
+		"brand-500": "#0ea5e9",
+		"cyan-400":  "#00ffff", // Overrides the Tailwind value
+		"evil":      `red" onload="alert(1)`,
+	}
+	SetColorResolver(ChainColorResolvers(brand, TailwindPalette))
+
+	tests := []struct {
+		value         string
+		expected      Color
+		expectedError string
+	}{
+		{value: "#fff", expected: "#fff"},
+		{value: "brand-500", expected: "#0ea5e9"},
+		{value: "cyan-400", expected: "#00ffff"},
+		{value: "neutral-900", expected: "#171717"},
+		{value: "brand-900", expectedError: "unknown color or token"},
+		{value: "evil", expectedError: "invalid color"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			color, err := resolveColor(tt.value)
+			if tt.expectedError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+					t.Errorf("resolveColor(%q) error = %v, want %q", tt.value, err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveColor(%q) unexpected error: %v", tt.value, err)
+			}
+			if color != tt.expected {
+				t.Errorf("resolveColor(%q) = %q, want %q", tt.value, color, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPalette_SetColorWithToken(t *testing.T) {
+	icon := &Icon{Name: "test-icon", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	result := makeSVGTag(icon.Config().SetColor("cyan-400").GetIcon())
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor" color="#22d3ee"><path d="M1 1"/></svg>`
+	if result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+
+	result = makeSVGTag(icon.Config().SetColor("brand-500").GetIcon())
+	if !strings.HasPrefix(result, "<!-- Error:") {
+		t.Errorf("expected error comment for unknown token, got %q", result)
+	}
+}
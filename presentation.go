@@ -0,0 +1,168 @@
+package templheroicons
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// presentationAttribute is an SVG presentation attribute (e.g., `fill="none"`).
+type presentationAttribute struct {
+	name  string
+	value string
+}
+
+// SetStrokeWidth overrides the stroke width of the icon (e.g., 1 for thinner Outline icons).
+// The width must be a positive number.
+func (b *IconBuilder) SetStrokeWidth(width float64) *IconBuilder {
+	if width <= 0 || math.IsNaN(width) || math.IsInf(width, 0) {
+		b.icon.setError(fmt.Errorf("invalid stroke width %v", width))
+		return b
+	}
+	b.icon.strokeWidth = width
+	return b
+}
+
+// SetFill overrides the fill paint of the icon: "none", a CSS color or a color token.
+// The value is validated when rendering: an invalid paint renders an error comment.
+func (b *IconBuilder) SetFill(paint Color) *IconBuilder {
+	b.icon.fill = paint
+	return b
+}
+
+// SetStroke overrides the stroke paint of the icon: "none", a CSS color or a color token.
+// The value is validated when rendering: an invalid paint renders an error comment.
+func (b *IconBuilder) SetStroke(paint Color) *IconBuilder {
+	b.icon.stroke = paint
+	return b
+}
+
+// AbsoluteStrokeWidth keeps the visual stroke width constant in pixels at any rendered size,
+// e.g. a 1.5 stroke stays 1.5px wide on an Outline icon rendered at 48px.
+// The stroke width is recomputed from the viewBox ratio for pixel sizes; for relative sizes
+// (em, rem, %) or omitted sizes, `vector-effect="non-scaling-stroke"` is used instead.
+func (b *IconBuilder) AbsoluteStrokeWidth() *IconBuilder {
+	b.icon.absoluteStrokeWidth = true
+	return b
+}
+
+// resolvePaint validates a fill or stroke paint, which is "none" or a color.
+func resolvePaint(value Color) (Color, error) {
+	if strings.TrimSpace(value.String()) == "none" {
+		return "none", nil
+	}
+	return resolveColor(value.String())
+}
+
+// getPresentationOverrides returns the validated fill, stroke-width and stroke overrides of the icon.
+func getPresentationOverrides(icon *Icon) ([]presentationAttribute, error) {
+	var overrides []presentationAttribute
+	if icon.fill != "" {
+		fill, err := resolvePaint(icon.fill)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fill: %w", err)
+		}
+		overrides = append(overrides, presentationAttribute{"fill", html.EscapeString(fill.String())})
+	}
+	if icon.strokeWidth != 0 {
+		overrides = append(overrides, presentationAttribute{"stroke-width", formatFloat(icon.strokeWidth)})
+	}
+	if icon.stroke != "" {
+		stroke, err := resolvePaint(icon.stroke)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stroke: %w", err)
+		}
+		overrides = append(overrides, presentationAttribute{"stroke", html.EscapeString(stroke.String())})
+	}
+	return overrides, nil
+}
+
+// applyAbsoluteStrokeWidth rescales the stroke-width override so that the stroke keeps its
+// width in pixels. It reports whether the size is not in pixels, in which case the body
+// needs a non-scaling stroke instead.
+func applyAbsoluteStrokeWidth(icon *Icon, overrides []presentationAttribute) ([]presentationAttribute, bool) {
+	strokeWidth, ok := findPresentationAttribute(overrides, "stroke-width")
+	if !ok {
+		strokeWidth, ok = findPresentationAttribute(typeAttributesMap[icon.Type], "stroke-width")
+	}
+	if !ok {
+		return overrides, false // No stroke to preserve
+	}
+
+	if icon.omitSize {
+		return overrides, true
+	}
+	width, height, err := getDimensions(icon)
+	if err != nil {
+		return overrides, false // Reported when rendering the dimensions
+	}
+	widthPx, widthOk := parsePixels(width)
+	heightPx, heightOk := parsePixels(height)
+	if !widthOk || !heightOk {
+		return overrides, true
+	}
+
+	base, err := strconv.ParseFloat(strokeWidth.value, 64)
+	if err != nil || widthPx == 0 || heightPx == 0 {
+		return overrides, false
+	}
+	viewBox := getViewBoxExtent(icon)
+
+	// The body is scaled uniformly by the smallest of the two ratios (preserveAspectRatio "meet")
+	scaled := base * viewBox / math.Min(widthPx, heightPx)
+	strokeWidth.value = formatFloat(math.Round(scaled*1000) / 1000)
+
+	rescaled := make([]presentationAttribute, 0, len(overrides)+1)
+	for _, override := range overrides {
+		if override.name != "stroke-width" {
+			rescaled = append(rescaled, override)
+		}
+	}
+	return append(rescaled, strokeWidth), false
+}
+
+// parsePixels returns the size in pixels, if it is unitless or in px.
+func parsePixels(size Size) (float64, bool) {
+	value, err := strconv.ParseFloat(strings.TrimSuffix(size.String(), "px"), 64)
+	return value, err == nil
+}
+
+// shapeElementPattern matches the opening of the SVG shape elements.
+var shapeElementPattern = regexp.MustCompile(`<(path|circle|ellipse|line|polygon|polyline|rect)\b`)
+
+// applyNonScalingStroke adds `vector-effect="non-scaling-stroke"` to the body shapes.
+// The property is not inherited, so it must be set on each element.
+func applyNonScalingStroke(body string) string {
+	return shapeElementPattern.ReplaceAllString(body, `<$1 vector-effect="non-scaling-stroke"`)
+}
+
+// findPresentationAttribute returns the attribute with the given name.
+func findPresentationAttribute(attrs []presentationAttribute, name string) (presentationAttribute, bool) {
+	for _, attr := range attrs {
+		if attr.name == name {
+			return attr, true
+		}
+	}
+	return presentationAttribute{}, false
+}
+
+// bodyPresentationPattern matches the fill, stroke and stroke-width attributes set on body elements.
+var bodyPresentationPattern = regexp.MustCompile(`\s(fill|stroke|stroke-width)="[^"]*"`)
+
+// applyPresentationOverrides rewrites the presentation attributes set on the body elements,
+// since they take precedence over the values inherited from the <svg> tag.
+func applyPresentationOverrides(body string, overrides []presentationAttribute) string {
+	if len(overrides) == 0 {
+		return body
+	}
+	return bodyPresentationPattern.ReplaceAllStringFunc(body, func(match string) string {
+		name := match[1:strings.IndexByte(match, '=')]
+		if override, ok := findPresentationAttribute(overrides, name); ok {
+			return fmt.Sprintf(` %s="%s"`, name, override.value)
+		}
+		return match
+	})
+}
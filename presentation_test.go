@@ -0,0 +1,148 @@
+package templheroicons
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/a-h/templ"
+)
+
+func TestPresentation_Overrides(t *testing.T) {
+	outline := &Icon{
+		Name: "outline-icon",
+		Size: "24",
+		Type: "Outline",
+		body: `<path fill="none" stroke="currentColor" stroke-linecap="round" stroke-linejoin="round" stroke-width="1.5" d="M1 1"/>`,
+	}
+	solid := &Icon{
+		Name: "solid-icon",
+		Size: "24",
+		Type: "Solid",
+		body: `<path fill="currentColor" fill-rule="evenodd" d="M1 1" clip-rule="evenodd"/>`,
+	}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Thinner Outline icon",
+			builder:  outline.Config().SetStrokeWidth(1),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1" stroke="currentColor"><path fill="none" stroke="currentColor" stroke-linecap="round" stroke-linejoin="round" stroke-width="1" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Outline icon with stroke color",
+			builder:  outline.Config().SetStroke("red-500").SetStrokeWidth(2.25),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="2.25" stroke="#ef4444"><path fill="none" stroke="#ef4444" stroke-linecap="round" stroke-linejoin="round" stroke-width="2.25" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Outlined-looking Solid icon",
+			builder:  solid.Config().SetFill("none").SetStroke(CurrentColor).SetStrokeWidth(1.5),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path fill="none" fill-rule="evenodd" d="M1 1" clip-rule="evenodd"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if outline.strokeWidth != 0 || solid.fill != "" {
+		t.Errorf("original icons modified")
+	}
+}
+
+func TestPresentation_InvalidValues(t *testing.T) {
+	icon := &Icon{Name: "test-icon", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name          string
+		builder       *IconBuilder
+		expectedError string
+	}{
+		{"Negative stroke width", icon.Config().SetStrokeWidth(-1), "invalid stroke width -1"},
+		{"Zero stroke width", icon.Config().SetStrokeWidth(0), "invalid stroke width 0"},
+		{"NaN stroke width", icon.Config().SetStrokeWidth(math.NaN()), "invalid stroke width NaN"},
+		{"Invalid fill", icon.Config().SetFill(`red" onload="x`), "invalid fill"},
+		{"Invalid stroke", icon.Config().SetStroke("brand-500"), "invalid stroke"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expectedError) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestPresentation_SetAttrsStaysProtected(t *testing.T) {
+	icon := &Icon{Name: "test-icon", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	result := makeSVGTag(icon.Config().SetAttrs(templ.Attributes{"fill": "red", "stroke-width": "9"}).GetIcon())
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`
+	if result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+}
+
+func TestPresentation_AbsoluteStrokeWidth(t *testing.T) {
+	outline := &Icon{
+		Name: "outline-icon",
+		Size: "24",
+		Type: "Outline",
+		body: `<path fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/>`,
+	}
+	micro := &Icon{Name: "micro-icon", Size: "16", Type: "Micro", body: `<path fill="currentColor" d="M1 1"/>`}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Large size keeps a thin stroke",
+			builder:  outline.Config().SetSize(48).AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 24 24" fill="none" stroke-width="0.75" stroke="currentColor"><path fill="none" stroke="currentColor" stroke-width="0.75" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Small size keeps a visible stroke",
+			builder:  outline.Config().SetSize(12).SetStrokeWidth(1).AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="12" height="12" viewBox="0 0 24 24" fill="none" stroke-width="2" stroke="currentColor"><path fill="none" stroke="currentColor" stroke-width="2" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Uneven dimensions use the smallest ratio",
+			builder:  outline.Config().SetWidth("72px").SetHeight("36").AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="72px" height="36" viewBox="0 0 24 24" fill="none" stroke-width="1" stroke="currentColor"><path fill="none" stroke="currentColor" stroke-width="1" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Relative size uses a non-scaling stroke",
+			builder:  outline.Config().SetSizeValue(SizeRem(2)).AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="2rem" height="2rem" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path vector-effect="non-scaling-stroke" fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Omitted size uses a non-scaling stroke",
+			builder:  outline.Config().OmitSize().AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path vector-effect="non-scaling-stroke" fill="none" stroke="currentColor" stroke-width="1.5" d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Icons without stroke are unchanged",
+			builder:  micro.Config().SetSize(48).AbsoluteStrokeWidth(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 16 16" fill="currentColor"><path fill="currentColor" d="M1 1"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,291 @@
+package templheroicons
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// IconType is the type of an icon, setting its default presentation attributes.
+type IconType string
+
+// Types of the heroicons variants.
+const (
+	TypeOutline IconType = "Outline" // Stroked with currentColor, 1.5 wide
+	TypeSolid   IconType = "Solid"   // Filled with currentColor
+	TypeMini    IconType = "Mini"    // Filled with currentColor
+	TypeMicro   IconType = "Micro"   // Filled with currentColor
+)
+
+// disallowedElements lists the elements able to run scripts or embed documents, rejected in
+// registered bodies. Names are lowercase, as HTML parsers match them in inline SVG.
+var disallowedElements = map[string]struct{}{
+	"script": {}, "foreignobject": {}, "iframe": {}, "embed": {}, "object": {},
+}
+
+// urlAttributes lists the attributes holding URLs, or animating them (e.g., <set to="...">),
+// checked for javascript: URLs in registered bodies.
+var urlAttributes = map[string]struct{}{
+	"href": {}, "src": {}, "action": {}, "formaction": {}, "to": {}, "from": {}, "values": {},
+}
+
+// RegisterOption configures the registration of icons.
+type RegisterOption func(*registerConfig)
+
+// registerConfig holds the options of Register and RegisterSet.
+type registerConfig struct {
+	trusted bool     // Skip the active content checks of the bodies
+	setType IconType // Type of the icons of a set
+}
+
+// WithTrustedBody registers bodies as-is, without rejecting active content such as scripts
+// and event handlers. Use it only for markup from a trusted origin (e.g., the application assets).
+func WithTrustedBody() RegisterOption {
+	return func(c *registerConfig) {
+		c.trusted = true
+	}
+}
+
+// WithSetType sets the type of the icons registered by RegisterSet, Outline by default like
+// `icons-maker` does. Register takes the type of its icon as an argument instead.
+func WithSetType(typ IconType) RegisterOption {
+	return func(c *registerConfig) {
+		c.setType = typ
+	}
+}
+
+// newRegisterConfig returns the registration configuration with the options applied.
+func newRegisterConfig(opts []RegisterOption) *registerConfig {
+	config := &registerConfig{setType: TypeOutline}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// registeredIcons holds the icons added with Register and RegisterSet, guarded by cacheMutex.
+// Their bodies are also stored in iconBodyCache, so that HeroiconsSource resolves them like the heroicons.
+var registeredIcons = map[string]registeredIcon{}
+
+// registeredIcon describes an icon added at runtime.
+type registeredIcon struct {
+	iconType IconType
+	viewBox  int
+}
+
+// Register adds an icon at runtime (e.g., a tenant logo), rendered by name like the heroicons
+// and configured with the same builder API. The body is the markup inside the <svg> tag, for a
+// square viewBox of width by height. Registering a name again replaces its icon; heroicons names
+// cannot be registered. Registered icons are provided by HeroiconsSource.
+//
+// Bodies with active content are rejected: <script>, <foreignObject> and embedding elements,
+// on* event handler attributes, and javascript: URLs. WithTrustedBody skips these checks.
+func Register(name string, body string, width, height int, typ IconType, opts ...RegisterOption) (*Icon, error) {
+	if err := validateRegisteredIcon(name, body, width, height, typ, newRegisterConfig(opts)); err != nil {
+		return nil, err
+	}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	if err := checkBuiltinConflicts(name); err != nil {
+		return nil, err
+	}
+	registerIcon(name, body, width, typ)
+	return newRegisteredIcon(name, registeredIcons[name]), nil
+}
+
+// RegisterSet adds the icons of an Iconify JSON file at runtime, as Outline icons unless
+// WithSetType is given, with the same checks and options as Register. The icons are registered
+// together: no icon is registered when one of them is invalid or conflicts with a heroicons name.
+func RegisterSet(fsys fs.FS, path string, opts ...RegisterOption) error {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return fmt.Errorf("failed to read icon set: %w", err)
+	}
+	if !gjson.ValidBytes(data) {
+		return fmt.Errorf("failed to parse icon set %s", path)
+	}
+
+	// Iconify dimensions default to the set dimensions, then to 16
+	defaultWidth, defaultHeight := gjson.GetBytes(data, "width"), gjson.GetBytes(data, "height")
+	dimension := func(icon gjson.Result, key string, fallback gjson.Result) int {
+		if value := icon.Get(key); value.Exists() {
+			return int(value.Int())
+		}
+		if fallback.Exists() {
+			return int(fallback.Int())
+		}
+		return 16
+	}
+
+	type setIcon struct {
+		name, body string
+		size       int
+	}
+	config := newRegisterConfig(opts)
+	var icons []setIcon
+	var setErr error
+	gjson.GetBytes(data, "icons").ForEach(func(key, value gjson.Result) bool {
+		name, body := key.String(), value.Get("body").String()
+		width, height := dimension(value, "width", defaultWidth), dimension(value, "height", defaultHeight)
+		if setErr = validateRegisteredIcon(name, body, width, height, config.setType, config); setErr != nil {
+			return false
+		}
+		icons = append(icons, setIcon{name: name, body: body, size: width})
+		return true
+	})
+	if setErr != nil {
+		return setErr
+	}
+	if len(icons) == 0 {
+		return fmt.Errorf("no icons found in icon set %s", path)
+	}
+
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	for _, icon := range icons {
+		if err := checkBuiltinConflicts(icon.name); err != nil {
+			return err
+		}
+	}
+	for _, icon := range icons {
+		registerIcon(icon.name, icon.body, icon.size, config.setType)
+	}
+	return nil
+}
+
+// RegisteredIcon returns a new icon for a name added with Register or RegisterSet.
+func RegisteredIcon(name string) (*Icon, bool) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	icon, found := registeredIcons[name]
+	if !found {
+		return nil, false
+	}
+	return newRegisteredIcon(name, icon), true
+}
+
+// newRegisteredIcon creates an icon for a registered name, its body resolved when rendering.
+func newRegisteredIcon(name string, icon registeredIcon) *Icon {
+	return &Icon{
+		Name:    name,
+		Type:    string(icon.iconType),
+		Size:    Size(strconv.Itoa(icon.viewBox)),
+		viewBox: float64(icon.viewBox),
+	}
+}
+
+// registerIcon stores a validated icon, with cacheMutex held.
+func registerIcon(name, body string, viewBox int, typ IconType) {
+	registeredIcons[name] = registeredIcon{iconType: typ, viewBox: viewBox}
+	iconBodyCache[name] = Body{SVG: body, ViewBox: viewBox}
+}
+
+// checkBuiltinConflicts returns an error if the name is a heroicons name, with cacheMutex held.
+func checkBuiltinConflicts(name string) error {
+	if _, registered := registeredIcons[name]; registered {
+		return nil
+	}
+	if _, cached := iconBodyCache[name]; !cached {
+		if err := loadIconBodies(); err != nil {
+			return err
+		}
+	}
+	if _, builtin := iconBodyCache[name]; builtin {
+		return fmt.Errorf("icon '%s' conflicts with a heroicons icon", name)
+	}
+	return nil
+}
+
+// validateRegisteredIcon validates an icon before registering it.
+func validateRegisteredIcon(name, body string, width, height int, typ IconType, config *registerConfig) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("missing icon name")
+	case width <= 0 || height <= 0:
+		return fmt.Errorf("invalid size %dx%d for icon '%s'", width, height, name)
+	case width != height:
+		return fmt.Errorf("non-square size %dx%d for icon '%s'", width, height, name)
+	}
+	if _, known := typeAttributesMap[string(typ)]; !known {
+		return fmt.Errorf("unknown icon type %q for icon '%s'", typ, name)
+	}
+	if err := validateBodyMarkup(body, config.trusted); err != nil {
+		return fmt.Errorf("invalid body for icon '%s': %w", name, err)
+	}
+	return nil
+}
+
+// ValidateBody checks that an icon body is well-formed markup without active content, with
+// the rules of Register (e.g., before storing bodies from untrusted uploads).
+func ValidateBody(body string) error {
+	return validateBodyMarkup(body, false)
+}
+
+// validateBodyMarkup checks that a body is well-formed markup, which cannot break the
+// <svg> tag it is rendered in, and without active content unless it is trusted.
+func validateBodyMarkup(body string, trusted bool) error {
+	if strings.TrimSpace(body) == "" {
+		return errors.New("empty body")
+	}
+	decoder := xml.NewDecoder(strings.NewReader(body))
+	for {
+		// The decoder reports unclosed and mismatched tags
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if element, ok := token.(xml.StartElement); ok && !trusted {
+			if err := checkActiveContent(element); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// checkActiveContent returns an error if an element can run scripts: a disallowed element,
+// an event handler attribute or a javascript: URL.
+func checkActiveContent(element xml.StartElement) error {
+	if _, disallowed := disallowedElements[strings.ToLower(element.Name.Local)]; disallowed {
+		return fmt.Errorf("disallowed <%s> element", element.Name.Local)
+	}
+	for _, attr := range element.Attr {
+		name := strings.ToLower(attr.Name.Local)
+		if strings.HasPrefix(name, "on") {
+			return fmt.Errorf("disallowed %s event handler on <%s>", attr.Name.Local, element.Name.Local)
+		}
+		if _, isURL := urlAttributes[name]; !isURL {
+			continue
+		}
+		// Animation values are separated by semicolons
+		for _, value := range strings.Split(attr.Value, ";") {
+			if isJavaScriptURL(value) {
+				return fmt.Errorf("disallowed javascript: URL in the %s attribute of <%s>", attr.Name.Local, element.Name.Local)
+			}
+		}
+	}
+	return nil
+}
+
+// isJavaScriptURL reports whether a URL uses the javascript: scheme. Browsers ignore the
+// whitespace and control characters in the scheme, and its case.
+func isJavaScriptURL(value string) bool {
+	scheme := strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, value)
+	return len(scheme) >= len("javascript:") && strings.EqualFold(scheme[:len("javascript:")], "javascript:")
+}
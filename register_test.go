@@ -0,0 +1,205 @@
+package templheroicons
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegister(t *testing.T) {
+	resetTestState()
+	defer resetTestState()
+
+	icon, err := Register("tenant-logo", `<circle cx="16" cy="16" r="12"/>`, 32, 32, TypeSolid)
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		icon     *Icon
+		expected string
+	}{
+		{
+			name:     "Registered icon",
+			icon:     icon,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="32" height="32" viewBox="0 0 32 32" fill="currentColor"><circle cx="16" cy="16" r="12"/></svg>`,
+		},
+		{
+			name:     "Configured registered icon",
+			icon:     icon.Config().SetSize(16).SetColor("sky-500").GetIcon(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" viewBox="0 0 32 32" fill="currentColor" color="#0ea5e9"><circle cx="16" cy="16" r="12"/></svg>`,
+		},
+		{
+			name:     "Icon referencing the registered name",
+			icon:     &Icon{Name: "tenant-logo", Type: "Solid", Size: "24"},
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 32 32" fill="currentColor"><circle cx="16" cy="16" r="12"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.icon); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	// Registering the name again replaces the icon
+	if _, err := Register("tenant-logo", `<path d="M1 1"/>`, 24, 24, TypeOutline); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	replaced, found := RegisteredIcon("tenant-logo")
+	if !found {
+		t.Fatal("RegisteredIcon() found no icon")
+	}
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`
+	if result := makeSVGTag(replaced); result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+
+	// Built-in icons are still resolved
+	if _, err := currentSource().Body("moon"); err != nil {
+		t.Errorf("Body() error = %v", err)
+	}
+	if _, found := RegisteredIcon("moon"); found {
+		t.Error("RegisteredIcon() found a built-in icon")
+	}
+}
+
+func TestRegister_Errors(t *testing.T) {
+	resetTestState()
+	defer resetTestState()
+
+	tests := []struct {
+		name          string
+		iconName      string
+		body          string
+		width, height int
+		iconType      IconType
+		expectedError string
+	}{
+		{"Built-in name", "moon", `<path d="M1 1"/>`, 24, 24, TypeOutline, "icon 'moon' conflicts with a heroicons icon"},
+		{"Missing name", " ", `<path d="M1 1"/>`, 24, 24, TypeOutline, "missing icon name"},
+		{"Invalid size", "logo", `<path d="M1 1"/>`, 0, 0, TypeOutline, "invalid size 0x0 for icon 'logo'"},
+		{"Non-square size", "logo", `<path d="M1 1"/>`, 32, 24, TypeOutline, "non-square size 32x24 for icon 'logo'"},
+		{"Unknown type", "logo", `<path d="M1 1"/>`, 24, 24, "Duotone", `unknown icon type "Duotone" for icon 'logo'`},
+		{"Empty body", "logo", "", 24, 24, TypeOutline, "invalid body for icon 'logo': empty body"},
+		{"Unclosed tag", "logo", `<g><path d="M1 1"/>`, 24, 24, TypeOutline, "invalid body for icon 'logo': XML syntax error"},
+		{"Closing the svg tag", "logo", `</svg><script>alert(1)</script>`, 24, 24, TypeOutline, "invalid body for icon 'logo': XML syntax error"},
+		{"Script", "logo", `<script>alert(1)</script>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed <script> element"},
+		{"Uppercase script", "logo", `<g><SCRIPT>alert(1)</SCRIPT></g>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed <SCRIPT> element"},
+		{"Foreign object", "logo", `<foreignObject><div/></foreignObject>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed <foreignObject> element"},
+		{"Event handler", "logo", `<path d="M1 1" onclick="alert(1)"/>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed onclick event handler on <path>"},
+		{"Event handler in a title", "logo", `<title><img src="x" OnError="alert(1)"/></title>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed OnError event handler on <img>"},
+		{"JavaScript link", "logo", `<a href="javascript:alert(1)"><path d="M1 1"/></a>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed javascript: URL in the href attribute of <a>"},
+		{"Obfuscated xlink", "logo", `<a xmlns:xlink="http://www.w3.org/1999/xlink" xlink:href=" Java&#x9;Script:alert(1)"/>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed javascript: URL in the href attribute of <a>"},
+		{"Animated link", "logo", `<a><set attributeName="href" to="javascript:alert(1)"/></a>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed javascript: URL in the to attribute of <set>"},
+		{"Animation values", "logo", `<animate attributeName="href" values="#a;javascript:alert(1)"/>`, 24, 24, TypeOutline, "invalid body for icon 'logo': disallowed javascript: URL in the values attribute of <animate>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Register(tt.iconName, tt.body, tt.width, tt.height, tt.iconType)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+				t.Errorf("Register() error = %v, want %q", err, tt.expectedError)
+			}
+		})
+	}
+
+	if len(registeredIcons) != 0 {
+		t.Errorf("invalid icons were registered: %v", registeredIcons)
+	}
+}
+
+func TestRegister_TrustedBody(t *testing.T) {
+	resetTestState()
+	defer resetTestState()
+
+	body := `<a href="/home" onclick="track()"><path d="M1 1"/></a>`
+	if _, err := Register("tracked-logo", body, 24, 24, TypeOutline, WithTrustedBody()); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if icon, found := RegisteredIcon("tracked-logo"); !found || !strings.Contains(makeSVGTag(icon), body) {
+		t.Errorf("RegisteredIcon() = %v, %v, want the trusted body", icon, found)
+	}
+
+	// Trusted bodies must still be well-formed
+	if _, err := Register("tracked-logo", `<a>`, 24, 24, TypeOutline, WithTrustedBody()); err == nil {
+		t.Error("Register() accepted a malformed trusted body")
+	}
+}
+
+func TestRegisterSet(t *testing.T) {
+	resetTestState()
+	defer resetTestState()
+
+	fsys := fstest.MapFS{
+		"icons/acme.json": {Data: []byte(`{
+			"prefix": "acme",
+			"icons": {
+				"rocket": {"body": "<path d=\"M1 1\"/>"},
+				"badge": {"body": "<path d=\"M2 2\"/>", "width": 16, "height": 16}
+			},
+			"width": 24,
+			"height": 24
+		}`)},
+		"icons/conflict.json": {Data: []byte(`{"icons": {"comet": {"body": "<path/>"}, "moon": {"body": "<path/>"}}}`)},
+		"icons/invalid.json":  {Data: []byte(`{"icons": {"comet": {"body": "<path>"}}}`)},
+		"icons/script.json":   {Data: []byte(`{"icons": {"comet": {"body": "<path onload=\"alert(1)\"/>"}}}`)},
+		"icons/wide.json":     {Data: []byte(`{"icons": {"comet": {"body": "<path/>", "width": 32}}, "height": 24}`)},
+		"icons/empty.json":    {Data: []byte(`{"icons": {}}`)},
+		"icons/broken.json":   {Data: []byte(`{"icons": `)},
+	}
+
+	if err := RegisterSet(fsys, "icons/acme.json", WithSetType(TypeSolid)); err != nil {
+		t.Fatalf("RegisterSet() error = %v", err)
+	}
+
+	for name, expected := range map[string]string{
+		"rocket": `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`,
+		"badge":  `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" viewBox="0 0 16 16" fill="currentColor"><path d="M2 2"/></svg>`,
+	} {
+		icon, found := RegisteredIcon(name)
+		if !found {
+			t.Fatalf("RegisteredIcon(%q) found no icon", name)
+		}
+		if result := makeSVGTag(icon); result != expected {
+			t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+		}
+	}
+
+	errorTests := []struct {
+		path          string
+		expectedError string
+	}{
+		{"icons/conflict.json", "icon 'moon' conflicts with a heroicons icon"},
+		{"icons/invalid.json", "invalid body for icon 'comet'"},
+		{"icons/script.json", "invalid body for icon 'comet': disallowed onload event handler on <path>"},
+		{"icons/wide.json", "non-square size 32x24 for icon 'comet'"},
+		{"icons/empty.json", "no icons found in icon set icons/empty.json"},
+		{"icons/broken.json", "failed to parse icon set icons/broken.json"},
+		{"icons/missing.json", "failed to read icon set"},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.path, func(t *testing.T) {
+			err := RegisterSet(fsys, tt.path)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+				t.Errorf("RegisterSet() error = %v, want %q", err, tt.expectedError)
+			}
+		})
+	}
+
+	// Sets are registered together: the icons before the conflict were not registered
+	if _, found := RegisteredIcon("comet"); found {
+		t.Error("RegisterSet() registered icons of a conflicting set")
+	}
+
+	// Trusted sets skip the active content checks
+	if err := RegisterSet(fsys, "icons/script.json", WithTrustedBody()); err != nil {
+		t.Errorf("RegisterSet() with trusted bodies error = %v", err)
+	}
+	if icon, found := RegisteredIcon("comet"); !found || icon.Type != string(TypeOutline) {
+		t.Errorf("RegisteredIcon() = %v, %v, want an Outline icon by default", icon, found)
+	}
+}
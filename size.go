@@ -0,0 +1,49 @@
+package templheroicons
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Size represents the size of UI components.
+type Size string
+
+// Sizes of the designed heroicons variants, in pixels.
+const (
+	Size16 Size = "16" // Micro icons
+	Size20 Size = "20" // Mini icons
+	Size24 Size = "24" // Outline and Solid icons
+)
+
+// String returns the string representation of a Size.
+func (s Size) String() string {
+	return string(s)
+}
+
+// SizePx returns a size in pixels (e.g., SizePx(18) is "18").
+func SizePx(value float64) Size {
+	return Size(formatFloat(value))
+}
+
+// SizeEm returns a size relative to the font size of the element (e.g., SizeEm(1) is "1em").
+func SizeEm(value float64) Size {
+	return Size(formatFloat(value) + "em")
+}
+
+// SizeRem returns a size relative to the root font size (e.g., SizeRem(1.5) is "1.5rem").
+func SizeRem(value float64) Size {
+	return Size(formatFloat(value) + "rem")
+}
+
+// sizePattern matches a non-negative CSS length, unitless or with a unit.
+var sizePattern = regexp.MustCompile(`^(\d+(\.\d+)?|\.\d+)(px|em|rem|ex|ch|%|vw|vh|vmin|vmax|pt|pc|cm|mm|in)?$`)
+
+// ParseSize validates a size such as "24", "1.5rem" or "100%" and returns it as a Size.
+func ParseSize(value string) (Size, error) {
+	value = strings.TrimSpace(value)
+	if !sizePattern.MatchString(value) {
+		return "", fmt.Errorf("invalid size %q", value)
+	}
+	return Size(value), nil
+}
@@ -0,0 +1,95 @@
+package templheroicons
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSize_Constructors(t *testing.T) {
+	tests := []struct {
+		size     Size
+		expected string
+	}{
+		{Size16, "16"},
+		{Size24, "24"},
+		{SizePx(18), "18"},
+		{SizePx(18.5), "18.5"},
+		{SizeEm(1), "1em"},
+		{SizeRem(1.5), "1.5rem"},
+	}
+
+	for _, tt := range tests {
+		if tt.size.String() != tt.expected {
+			t.Errorf("got %q, want %q", tt.size, tt.expected)
+		}
+		if _, err := ParseSize(tt.size.String()); err != nil {
+			t.Errorf("ParseSize(%q) unexpected error: %v", tt.size, err)
+		}
+	}
+}
+
+func TestSize_ParseSize(t *testing.T) {
+	valid := []string{"24", "1.5rem", ".5em", "100%", "12px", " 32 "}
+	for _, value := range valid {
+		if _, err := ParseSize(value); err != nil {
+			t.Errorf("ParseSize(%q) unexpected error: %v", value, err)
+		}
+	}
+
+	invalid := []string{"", "-1", "24 px", "1.5.rem", "auto", `24" onload="alert(1)`, "24furlongs"}
+	for _, value := range invalid {
+		if _, err := ParseSize(value); err == nil {
+			t.Errorf("ParseSize(%q) expected error, got nil", value)
+		}
+	}
+}
+
+func TestSize_Builder(t *testing.T) {
+	icon := &Icon{Name: "test-icon", Size: "24", Type: "Solid", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Size with units",
+			builder:  icon.Config().SetSizeValue(SizeRem(1.5)),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="1.5rem" height="1.5rem" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Width and height overrides",
+			builder:  icon.Config().SetWidth(SizePx(32)).SetHeight(SizeEm(1)),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="32" height="1em" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Width override keeps height",
+			builder:  icon.Config().SetWidth(Size16),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="24" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Omitted size",
+			builder:  icon.Config().OmitSize().SetAttrs(map[string]any{"class": "size-6"}),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24" fill="currentColor" class="size-6"><path d="M1 1"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	// Invalid sizes are rejected instead of being written into the tag
+	result := makeSVGTag(icon.Config().SetSizeValue(`24" onload="alert(1)`).GetIcon())
+	if !strings.HasPrefix(result, "<!-- Error: invalid size") {
+		t.Errorf("expected error comment, got %q", result)
+	}
+
+	// The original icon is unchanged
+	if icon.width != "" || icon.height != "" || icon.omitSize {
+		t.Errorf("original icon modified: %+v", icon)
+	}
+}
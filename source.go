@@ -0,0 +1,229 @@
+package templheroicons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// ErrIconNotFound is wrapped by the errors of sources that do not provide an icon.
+var ErrIconNotFound = errors.New("icon not found")
+
+// Body is the SVG markup of an icon.
+type Body struct {
+	SVG     string // Markup inside the <svg> tag
+	ViewBox int    // Size of the square viewBox, 0 for the default of the icon type
+}
+
+// Source provides the bodies of icons by name (e.g., the embedded heroicons, a folder of overrides).
+type Source interface {
+	// Body returns the body of the icon, or an error wrapping ErrIconNotFound if the source
+	// does not provide it.
+	Body(name string) (Body, error)
+}
+
+// SourceFunc adapts a function to the Source interface.
+type SourceFunc func(name string) (Body, error)
+
+// Body calls f(name).
+func (f SourceFunc) Body(name string) (Body, error) {
+	return f(name)
+}
+
+// MapSource is a Source of in-memory icon bodies.
+type MapSource map[string]Body
+
+// Body returns the body of the icon from the map.
+func (m MapSource) Body(name string) (Body, error) {
+	if body, ok := m[name]; ok {
+		return body, nil
+	}
+	return Body{}, notFoundError(name)
+}
+
+// ChainSources returns a Source trying each source in order, e.g. overrides over HeroiconsSource.
+// The next source is tried only when an icon is not found; other errors are returned.
+func ChainSources(sources ...Source) Source {
+	return SourceFunc(func(name string) (Body, error) {
+		for _, source := range sources {
+			body, err := source.Body(name)
+			if !errors.Is(err, ErrIconNotFound) {
+				return body, err
+			}
+		}
+		return Body{}, notFoundError(name)
+	})
+}
+
+// FSSource returns a Source reading the icons of an Iconify JSON file from any fs.FS
+// (e.g., os.DirFS, embed.FS). The file is read once, when the first icon is requested.
+func FSSource(fsys fs.FS, path string) Source {
+	return &fsSource{fsys: fsys, path: path}
+}
+
+// fsSource is a Source reading an Iconify JSON file.
+type fsSource struct {
+	fsys fs.FS
+	path string
+
+	once   sync.Once
+	bodies map[string]Body
+	err    error
+}
+
+// Body returns the body of the icon from the Iconify JSON file.
+func (s *fsSource) Body(name string) (Body, error) {
+	s.once.Do(func() {
+		data, err := fs.ReadFile(s.fsys, s.path)
+		if err != nil {
+			s.err = fmt.Errorf("failed to read icon set: %w", err)
+			return
+		}
+		if !gjson.ValidBytes(data) {
+			s.err = fmt.Errorf("failed to parse icon set %s", s.path)
+			return
+		}
+		s.bodies = make(map[string]Body)
+		parseIconifyBodies(data, 16, s.bodies)
+	})
+	if s.err != nil {
+		return Body{}, s.err
+	}
+	if body, ok := s.bodies[name]; ok {
+		return body, nil
+	}
+	return Body{}, notFoundError(name)
+}
+
+// parseIconifyBodies adds the icons of an Iconify JSON dataset to the bodies. The viewBox
+// is the width of the icon, or of the dataset, or the default size.
+func parseIconifyBodies(data []byte, defaultSize int, bodies map[string]Body) {
+	if width := gjson.GetBytes(data, "width"); width.Exists() {
+		defaultSize = int(width.Int())
+	}
+	gjson.GetBytes(data, "icons").ForEach(func(key, value gjson.Result) bool {
+		body := Body{SVG: value.Get("body").String(), ViewBox: defaultSize}
+		if width := value.Get("width"); width.Exists() {
+			body.ViewBox = int(width.Int())
+		}
+		bodies[key.String()] = body
+		return true
+	})
+}
+
+// HeroiconsSource is the Source of the embedded heroicons, and of the icons added with Register.
+var HeroiconsSource Source = heroiconsSource{}
+
+// heroiconsSource is the Source of the embedded heroicons dataset, with thread-safe caching.
+type heroiconsSource struct{}
+
+// Body returns the body of the icon from the cache, loading the dataset on the first miss.
+func (heroiconsSource) Body(name string) (Body, error) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
+
+	// Check if the body is already cached
+	if body, found := iconBodyCache[name]; found {
+		return body, nil
+	}
+
+	if err := loadIconBodies(); err != nil {
+		return Body{}, err
+	}
+
+	// Return the requested icon body from the cache
+	body, exists := iconBodyCache[name]
+	if !exists {
+		return Body{}, notFoundError(name)
+	}
+	return body, nil
+}
+
+// loadIconBodies reads the bodies of the heroicons into the cache once, with cacheMutex held.
+// A failed load is retried on the next call.
+func loadIconBodies() error {
+	if iconBodiesLoaded {
+		return nil
+	}
+
+	// Read and parse the JSON file containing icon data
+	jsonFilename := "data/heroicons_cache.json"
+	heroiconsData, err := heroiconsJSONSource.Open(jsonFilename)
+	if err != nil {
+		return fmt.Errorf("failed to read heroicons JSON: %w", err)
+	}
+	defer heroiconsData.Close()
+
+	data, _ := io.ReadAll(heroiconsData)
+
+	// Check if the JSON data is valid
+	if !gjson.ValidBytes(data) {
+		return fmt.Errorf("failed to parse heroicons JSON")
+	}
+
+	// Populate the cache with the icons, keeping the viewBox of the type default when unset
+	parseIconifyBodies(data, 0, iconBodyCache)
+	iconBodiesLoaded = true
+	return nil
+}
+
+// notFoundError is the error of a source without the named icon, matching ErrIconNotFound.
+type notFoundError string
+
+// Error returns the message of the error.
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("icon '%s' not found", string(e))
+}
+
+// Is reports whether the target is ErrIconNotFound.
+func (e notFoundError) Is(target error) bool {
+	return target == ErrIconNotFound
+}
+
+// Active icon source, shared by all renders without a context source
+var (
+	iconSource      Source = HeroiconsSource
+	iconSourceMutex sync.RWMutex
+)
+
+// SetSource replaces the source of the icon bodies for the whole process, nil restoring
+// HeroiconsSource. Use ChainSources to layer overrides over HeroiconsSource.
+// Icons with their own body (e.g., created with NewIcon) are not looked up.
+func SetSource(source Source) {
+	if source == nil {
+		source = HeroiconsSource
+	}
+	iconSourceMutex.Lock()
+	defer iconSourceMutex.Unlock()
+	iconSource = source
+}
+
+// currentSource returns the process-wide icon source.
+func currentSource() Source {
+	iconSourceMutex.RLock()
+	defer iconSourceMutex.RUnlock()
+	return iconSource
+}
+
+// sourceKey is the context key holding the Source.
+type sourceKey struct{}
+
+// WithSource returns a context rendering icons with the source instead of the process-wide
+// source (e.g., per-tenant overrides). The variants and overlays of Duotone, SetSizeAuto,
+// Stack and Badge are looked up in the source too.
+func WithSource(ctx context.Context, source Source) context.Context {
+	return context.WithValue(ctx, sourceKey{}, source)
+}
+
+// SourceFromContext returns the source set with WithSource, or the process-wide source.
+func SourceFromContext(ctx context.Context) Source {
+	if source, ok := ctx.Value(sourceKey{}).(Source); ok && source != nil {
+		return source
+	}
+	return currentSource()
+}
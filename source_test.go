@@ -0,0 +1,226 @@
+package templheroicons
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMapSource(t *testing.T) {
+	source := MapSource{"logo": {SVG: `<path d="M1 1"/>`, ViewBox: 32}}
+
+	body, err := source.Body("logo")
+	if err != nil || body.SVG != `<path d="M1 1"/>` || body.ViewBox != 32 {
+		t.Errorf("Body() = %+v, %v", body, err)
+	}
+
+	_, err = source.Body("missing")
+	if !errors.Is(err, ErrIconNotFound) || err.Error() != "icon 'missing' not found" {
+		t.Errorf("Body() error = %v, want icon not found", err)
+	}
+}
+
+func TestChainSources(t *testing.T) {
+	failing := SourceFunc(func(name string) (Body, error) {
+		if name == "broken" {
+			return Body{}, errors.New("source unavailable")
+		}
+		return Body{}, notFoundError(name)
+	})
+	overrides := MapSource{"moon": {SVG: `<path d="M1 1"/>`}}
+	source := ChainSources(failing, overrides, HeroiconsSource)
+
+	tests := []struct {
+		name          string
+		iconName      string
+		expectedBody  string
+		expectedError string
+	}{
+		{"Override", "moon", `<path d="M1 1"/>`, ""},
+		{"Fallback", "academic-cap", `<path fill="none" stroke="currentColor" stroke-linecap="round"`, ""},
+		{"Not found in any source", "missing", "", "icon 'missing' not found"},
+		{"Other errors are returned", "broken", "", "source unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := source.Body(tt.iconName)
+			if tt.expectedError != "" {
+				if err == nil || err.Error() != tt.expectedError {
+					t.Errorf("Body() error = %v, want %q", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil || !strings.HasPrefix(body.SVG, tt.expectedBody) {
+				t.Errorf("Body() = %q, %v, want %q", body.SVG, err, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestFSSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		"icons/acme.json": {Data: []byte(`{
+			"icons": {
+				"rocket": {"body": "<path d=\"M1 1\"/>"},
+				"badge": {"body": "<path d=\"M2 2\"/>", "width": 16, "height": 16}
+			},
+			"width": 32,
+			"height": 32
+		}`)},
+		"icons/default.json": {Data: []byte(`{"icons": {"dot": {"body": "<circle/>"}}}`)},
+		"icons/broken.json":  {Data: []byte(`{"icons": `)},
+	}
+
+	tests := []struct {
+		name          string
+		source        Source
+		iconName      string
+		expected      Body
+		expectedError string
+	}{
+		{"Set viewBox", FSSource(fsys, "icons/acme.json"), "rocket", Body{SVG: `<path d="M1 1"/>`, ViewBox: 32}, ""},
+		{"Icon viewBox", FSSource(fsys, "icons/acme.json"), "badge", Body{SVG: `<path d="M2 2"/>`, ViewBox: 16}, ""},
+		{"Iconify default viewBox", FSSource(fsys, "icons/default.json"), "dot", Body{SVG: `<circle/>`, ViewBox: 16}, ""},
+		{"Icon not found", FSSource(fsys, "icons/acme.json"), "missing", Body{}, "icon 'missing' not found"},
+		{"Invalid JSON", FSSource(fsys, "icons/broken.json"), "rocket", Body{}, "failed to parse icon set icons/broken.json"},
+		{"Missing file", FSSource(fsys, "icons/missing.json"), "rocket", Body{}, "failed to read icon set"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := tt.source.Body(tt.iconName)
+			if tt.expectedError != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.expectedError) {
+					t.Errorf("Body() error = %v, want %q", err, tt.expectedError)
+				}
+				return
+			}
+			if err != nil || body != tt.expected {
+				t.Errorf("Body() = %+v, %v, want %+v", body, err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHeroiconsSource_LoadsOnce(t *testing.T) {
+	resetTestState()
+	defer resetTestState()
+
+	opens := 0
+	heroiconsJSONSource = openCounter{FS: heroiconsJSON, opens: &opens}
+	defer func() {
+		heroiconsJSONSource = heroiconsJSON
+	}()
+
+	// Misses after the first load are reported without parsing the dataset again
+	for _, name := range []string{"missing", "other-missing", "moon", "missing"} {
+		_, err := HeroiconsSource.Body(name)
+		if name == "moon" && err != nil {
+			t.Errorf("Body(%q) error = %v", name, err)
+		} else if name != "moon" && !errors.Is(err, ErrIconNotFound) {
+			t.Errorf("Body(%q) error = %v, want icon not found", name, err)
+		}
+	}
+	if opens != 1 {
+		t.Errorf("the dataset was read %d times, want once", opens)
+	}
+}
+
+// openCounter counts the files opened in a file system.
+type openCounter struct {
+	fs.FS
+	opens *int
+}
+
+func (c openCounter) Open(name string) (fs.File, error) {
+	*c.opens++
+	return c.FS.Open(name)
+}
+
+func TestSetSource(t *testing.T) {
+	defer SetSource(nil)
+
+	SetSource(ChainSources(MapSource{"moon": {SVG: `<path d="M1 1"/>`}}, HeroiconsSource))
+	icon := &Icon{Name: "moon", Type: "Outline", Size: "24"}
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`
+	if result := makeSVGTag(icon); result != expected {
+		t.Errorf("makeSVGTag() = %q, want %q", result, expected)
+	}
+
+	SetSource(nil)
+	if SourceFromContext(context.Background()) != HeroiconsSource {
+		t.Error("SetSource(nil) did not restore HeroiconsSource")
+	}
+	if result := makeSVGTag(icon); result == expected {
+		t.Errorf("makeSVGTag() = %q, want the heroicons body", result)
+	}
+}
+
+func TestWithSource(t *testing.T) {
+	tenant := MapSource{
+		"moon": {SVG: `<path d="M1 1"/>`},
+		"logo": {SVG: `<circle cx="16" cy="16" r="12"/>`, ViewBox: 32},
+	}
+	ctx := WithSource(context.Background(), ChainSources(tenant, HeroiconsSource))
+
+	render := func(ctx context.Context, icon *Icon) string {
+		var buffer bytes.Buffer
+		if err := icon.Render().Render(ctx, &buffer); err != nil {
+			t.Fatalf("Render() error = %v", err)
+		}
+		return buffer.String()
+	}
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		icon     *Icon
+		expected string
+	}{
+		{
+			name:     "Context source",
+			ctx:      ctx,
+			icon:     Moon,
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Context source with viewBox",
+			ctx:      ctx,
+			icon:     &Icon{Name: "logo", Type: "Solid", Size: "24"},
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 32 32" fill="currentColor"><circle cx="16" cy="16" r="12"/></svg>`,
+		},
+		{
+			name:     "Context source falls back",
+			ctx:      ctx,
+			icon:     MoonMicro.Config().SetColor("sky-500").GetIcon(),
+			expected: makeSVGTag(MoonMicro.Config().SetColor("sky-500").GetIcon()),
+		},
+		{
+			name:     "Icon with its own body",
+			ctx:      ctx,
+			icon:     NewIcon("moon", "Outline", 24, `<path d="M2 2"/>`),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M2 2"/></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := render(tt.ctx, tt.icon); result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	// Rendering with the context source does not change the icon for other renders
+	if result := render(context.Background(), Moon); strings.Contains(result, `d="M1 1"`) {
+		t.Errorf("Render() = %q, want the heroicons body", result)
+	}
+	if Moon.body != "" {
+		t.Error("Render() cached the body in the shared icon")
+	}
+}
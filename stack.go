@@ -0,0 +1,225 @@
+package templheroicons
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Position represents where an overlay is placed over the base icon.
+type Position int
+
+const (
+	TopRight Position = iota
+	TopLeft
+	BottomRight
+	BottomLeft
+	Center
+)
+
+// StackOption configures a stacked or badged icon.
+type StackOption func(*stackConfig)
+
+// stackConfig holds the options of Stack and Badge.
+type stackConfig struct {
+	knockout   float64 // Gap around the overlay cut out of the base icon, in viewBox units
+	background Color   // Badge background color
+	text       Color   // Badge text color
+}
+
+// WithKnockout cuts a gap of the given width (in viewBox units) around the overlay
+// out of the base icon, so the overlay stays readable.
+func WithKnockout(gap float64) StackOption {
+	return func(c *stackConfig) {
+		c.knockout = gap
+	}
+}
+
+// WithBadgeColors sets the background and text colors of a badge.
+func WithBadgeColors(background, text Color) StackOption {
+	return func(c *stackConfig) {
+		c.background = background
+		c.text = text
+	}
+}
+
+// composition composes an overlay over the resolved icon, looking up bodies in the source.
+type composition func(icon *Icon, source Source) error
+
+// Stack composes the overlay icon over the base icon in a single <svg>
+// (e.g., a bell with a dot, a folder with a plus). The overlay is scaled to the
+// given fraction of the base icon (0 < scale <= 1) and placed at the position.
+// The overlay color, if set, is kept. The result is configured like any other icon.
+// Both bodies are looked up when rendering, in the source of the context (see WithSource).
+func Stack(base, overlay *Icon, position Position, scale float64, opts ...StackOption) *IconBuilder {
+	builder := ConfigureIcon(base)
+	config := newStackConfig(opts)
+	if scale <= 0 || scale > 1 || math.IsNaN(scale) {
+		builder.icon.setError(fmt.Errorf("invalid overlay scale %v", scale))
+		return builder
+	}
+	overlay = overlay.clone()
+	var color string
+	if overlay.Color != "" {
+		resolved, err := resolveColor(overlay.Color.String())
+		if err != nil {
+			builder.icon.setError(err)
+			return builder
+		}
+		color = fmt.Sprintf(` color="%s"`, html.EscapeString(resolved.String()))
+	}
+
+	builder.icon.compositions = append(builder.icon.compositions, func(icon *Icon, source Source) error {
+		resolved, err := overlay.resolve(source)
+		if err != nil {
+			return err
+		}
+
+		viewBox := icon.viewBoxSize()
+		size := viewBox * scale
+		x, y := positionOffset(position, viewBox, size)
+		if config.knockout > 0 {
+			applyKnockout(icon, x+size/2, y+size/2, size/2+config.knockout)
+		}
+		icon.layers += fmt.Sprintf(`<g transform="translate(%s %s) scale(%s)"%s%s>%s</g>`,
+			formatCoordinate(x), formatCoordinate(y), formatCoordinate(size/resolved.viewBoxSize()),
+			color, getTypeAttributes(resolved.Type), resolved.body)
+		return nil
+	})
+	return builder
+}
+
+// Badge composes a numeric bubble over the top-right corner of the icon
+// (e.g., unread notifications). The bubble stays upright in the corner when the icon
+// is rotated, flipped or mirrored for RTL. Counts above 99 are shown as "99+", and a zero
+// count renders the icon without bubble. Negative counts render an error comment.
+// The bubble is red with white text by default, see WithBadgeColors.
+func Badge(icon *Icon, count int, opts ...StackOption) *IconBuilder {
+	builder := ConfigureIcon(icon)
+	if count < 0 {
+		builder.icon.setError(fmt.Errorf("invalid badge count %d", count))
+		return builder
+	}
+	if count == 0 {
+		return builder
+	}
+	config := newStackConfig(opts)
+	background, err := resolvePaint(config.background)
+	if err != nil {
+		builder.icon.setError(fmt.Errorf("invalid badge background: %w", err))
+		return builder
+	}
+	text, err := resolvePaint(config.text)
+	if err != nil {
+		builder.icon.setError(fmt.Errorf("invalid badge text: %w", err))
+		return builder
+	}
+
+	label := strconv.Itoa(count)
+	if count > 99 {
+		label = "99+"
+	}
+
+	opts = append([]StackOption(nil), opts...)
+	builder.icon.compositions = append(builder.icon.compositions, func(icon *Icon, _ Source) error {
+		// The viewBox of the resolved body sizes the bubble (e.g., a source with 32x32 icons)
+		viewBox := icon.viewBoxSize()
+		config := newStackConfig(append([]StackOption{WithKnockout(viewBox / 16)}, opts...))
+		radius := viewBox * 0.3
+		cx, cy := viewBox-radius, radius
+		if config.knockout > 0 {
+			icon.knockouts = append(icon.knockouts, knockout{cx: cx, cy: cy, r: radius + config.knockout, upright: true})
+		}
+		icon.labels += fmt.Sprintf(
+			`<g stroke="none"><circle cx="%[1]s" cy="%[2]s" r="%[3]s" fill="%[4]s"/><text x="%[1]s" y="%[2]s" fill="%[5]s" font-family="system-ui,sans-serif" font-size="%[6]s" font-weight="600" text-anchor="middle" dominant-baseline="central">%[7]s</text></g>`,
+			formatCoordinate(cx), formatCoordinate(cy), formatCoordinate(radius),
+			html.EscapeString(background.String()), html.EscapeString(text.String()),
+			formatCoordinate(radius*badgeFontRatio(label)), label,
+		)
+		return nil
+	})
+	return builder
+}
+
+// newStackConfig returns the stack configuration with the options applied.
+func newStackConfig(opts []StackOption) *stackConfig {
+	config := &stackConfig{background: "#ef4444", text: "#fff"}
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
+
+// badgeFontRatio returns the font size of the badge label relative to the bubble radius.
+func badgeFontRatio(label string) float64 {
+	switch len(label) {
+	case 1:
+		return 1.4
+	case 2:
+		return 1.1
+	default:
+		return 0.8
+	}
+}
+
+// positionOffset returns the top-left corner of an overlay of the given size.
+func positionOffset(position Position, viewBox, size float64) (float64, float64) {
+	switch position {
+	case TopLeft:
+		return 0, 0
+	case BottomRight:
+		return viewBox - size, viewBox - size
+	case BottomLeft:
+		return 0, viewBox - size
+	case Center:
+		return (viewBox - size) / 2, (viewBox - size) / 2
+	default: // TopRight
+		return viewBox - size, 0
+	}
+}
+
+// knockout is a circular hole cut out of the body of an icon, in viewBox units.
+type knockout struct {
+	cx, cy, r float64
+	upright   bool // Cut out of the transformed icon, around a label
+}
+
+// applyKnockout cuts a circular hole out of the body of the icon, so that the
+// overlay drawn on top of it has a gap around it.
+func applyKnockout(icon *Icon, cx, cy, r float64) {
+	icon.knockouts = append(icon.knockouts, knockout{cx: cx, cy: cy, r: r})
+}
+
+// applyKnockouts masks the body with the knockouts of the icon around overlays, or around
+// labels if upright, and returns the mask definition. The mask ID is numbered with the
+// context, so repeated icons never share a mask.
+func applyKnockouts(ctx context.Context, icon *Icon, body string, upright bool) (string, string) {
+	var holes []knockout
+	for _, hole := range icon.knockouts {
+		if hole.upright == upright {
+			holes = append(holes, hole)
+		}
+	}
+	if len(holes) == 0 {
+		return body, ""
+	}
+	id := nextID(ctx, "mask")
+	viewBox := formatCoordinate(icon.viewBoxSize())
+
+	var mask strings.Builder
+	fmt.Fprintf(&mask, `<mask id="%[1]s"><rect width="%[2]s" height="%[2]s" fill="#fff"/>`, id, viewBox)
+	for _, hole := range holes {
+		fmt.Fprintf(&mask, `<circle cx="%s" cy="%s" r="%s" fill="#000"/>`,
+			formatCoordinate(hole.cx), formatCoordinate(hole.cy), formatCoordinate(hole.r))
+	}
+	mask.WriteString("</mask>")
+	return fmt.Sprintf(`<g mask="url(#%s)">%s</g>`, id, body), mask.String()
+}
+
+// formatCoordinate formats a coordinate rounded to 3 decimals.
+func formatCoordinate(value float64) string {
+	return formatFloat(math.Round(value*1000) / 1000)
+}
@@ -0,0 +1,198 @@
+package templheroicons
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestStack_Compose(t *testing.T) {
+	bell := &Icon{Name: "bell", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+	plus := &Icon{Name: "plus-circle-16-solid", Size: "16", Type: "Micro", body: `<path d="M2 2"/>`}
+
+	knockoutMask := `<rect width="24" height="24" fill="#fff"/><circle cx="12" cy="12" r="8" fill="#000"/>`
+	knockoutID := "templheroicons-mask-1"
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Overlay at top right",
+			builder:  Stack(bell, plus, TopRight, 0.5),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/><g transform="translate(12 0) scale(0.75)" fill="currentColor"><path d="M2 2"/></g></svg>`,
+		},
+		{
+			name:     "Colored overlay at bottom left",
+			builder:  Stack(bell, plus.Config().SetColor("red-500").GetIcon(), BottomLeft, 0.25),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/><g transform="translate(0 18) scale(0.375)" color="#ef4444" fill="currentColor"><path d="M2 2"/></g></svg>`,
+		},
+		{
+			name:     "Centered overlay with knockout",
+			builder:  Stack(bell, plus, Center, 0.5, WithKnockout(2)),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><defs><mask id="` + knockoutID + `">` + knockoutMask + `</mask></defs><g mask="url(#` + knockoutID + `)"><path d="M1 1"/></g><g transform="translate(6 6) scale(0.75)" fill="currentColor"><path d="M2 2"/></g></svg>`,
+		},
+		{
+			name:     "Stacked icons are configurable",
+			builder:  Stack(bell, plus, TopLeft, 0.5).SetSize(48).SetStrokeWidth(2),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 24 24" fill="none" stroke-width="2" stroke="currentColor"><path d="M1 1"/><g transform="translate(0 0) scale(0.75)" fill="currentColor"><path d="M2 2"/></g></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTagContext(WithIDScope(context.Background()), tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTagContext() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if len(bell.compositions) != 0 {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestStack_Errors(t *testing.T) {
+	bell := &Icon{Name: "bell", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name          string
+		builder       *IconBuilder
+		expectedError string
+	}{
+		{"Invalid scale", Stack(bell, bell, TopRight, 1.5), "invalid overlay scale 1.5"},
+		{"Missing overlay", Stack(bell, &Icon{Name: "non-existing-icon", Type: "Solid"}, TopRight, 0.5), "icon 'non-existing-icon' not found"},
+		{"Invalid badge color", Badge(bell, 3, WithBadgeColors("brand-500", "#fff")), "invalid badge background"},
+		{"Negative badge count", Badge(bell, -4), "invalid badge count -4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := makeSVGTag(tt.builder.GetIcon())
+			if !strings.HasPrefix(result, "<!-- Error: "+tt.expectedError) {
+				t.Errorf("makeSVGTag() = %q, want error %q", result, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestStack_Badge(t *testing.T) {
+	tests := []struct {
+		count    int
+		label    string
+		fontSize string
+	}{
+		{3, ">3</text>", `font-size="10.08"`},
+		{42, ">42</text>", `font-size="7.92"`},
+		{120, ">99+</text>", `font-size="5.76"`},
+	}
+
+	for _, tt := range tests {
+		// Uses the real dataset through the body cache
+		result := makeSVGTag(Badge(Bell, tt.count).GetIcon())
+		for _, expected := range []string{
+			`<circle cx="16.8" cy="7.2" r="7.2" fill="#ef4444"/>`,
+			`<mask id="templheroicons-global-mask-`,
+			`<circle cx="16.8" cy="7.2" r="8.7" fill="#000"/>`,
+			`fill="#fff" font-family="system-ui,sans-serif"`,
+			tt.fontSize,
+			tt.label,
+		} {
+			if !strings.Contains(result, expected) {
+				t.Errorf("Badge(%d) = %q, expected %q", tt.count, result, expected)
+			}
+		}
+	}
+
+	result := makeSVGTag(Badge(Bell, 1, WithKnockout(0), WithBadgeColors("blue-600", CurrentColor)).GetIcon())
+	if strings.Contains(result, "<mask") || !strings.Contains(result, `fill="#2563eb"`) || !strings.Contains(result, `fill="currentColor" font-family`) {
+		t.Errorf("Badge() with options = %q", result)
+	}
+}
+
+func TestStack_BadgeZeroCount(t *testing.T) {
+	if result, expected := makeSVGTag(Badge(Bell, 0).GetIcon()), makeSVGTag(Bell); result != expected {
+		t.Errorf("Badge(0) = %q, want the plain icon %q", result, expected)
+	}
+}
+
+func TestStack_UniqueMaskIDs(t *testing.T) {
+	icon := Badge(Bell, 3).GetIcon()
+	ctx := WithIDScope(context.Background())
+
+	first, second := makeSVGTagContext(ctx, icon), makeSVGTagContext(ctx, icon)
+	for _, tt := range []struct{ result, id string }{{first, "templheroicons-mask-1"}, {second, "templheroicons-mask-2"}} {
+		if !strings.Contains(tt.result, `<mask id="`+tt.id+`">`) || !strings.Contains(tt.result, `mask="url(#`+tt.id+`)"`) {
+			t.Errorf("makeSVGTagContext() = %q, want mask %q", tt.result, tt.id)
+		}
+	}
+}
+
+func TestStack_BadgeUpright(t *testing.T) {
+	bell := &Icon{Name: "bell", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+	result := makeSVGTagContext(WithIDScope(context.Background()), Badge(bell, 3).FlipH().GetIcon())
+
+	// The knockout and the bubble are drawn over the flipped icon, in the top-right corner
+	expected := `<defs><mask id="templheroicons-mask-1"><rect width="24" height="24" fill="#fff"/><circle cx="16.8" cy="7.2" r="8.7" fill="#000"/></mask></defs><g mask="url(#templheroicons-mask-1)"><g transform="translate(24 0) scale(-1 1)"><path d="M1 1"/></g></g><g stroke="none"><circle cx="16.8"`
+	if !strings.Contains(result, expected) {
+		t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+	}
+}
+
+func TestStack_SourceViewBox(t *testing.T) {
+	SetSource(MapSource{"logo": {SVG: `<path d="M1 1"/>`, ViewBox: 32}})
+	defer SetSource(nil)
+
+	logo := &Icon{Name: "logo", Type: "Solid", Size: "24"}
+	plus := &Icon{Name: "plus-circle-16-solid", Size: "16", Type: "Micro", body: `<path d="M2 2"/>`}
+
+	// Overlays and bubbles are placed in the 32x32 viewBox of the source body
+	for _, tt := range []struct {
+		builder  *IconBuilder
+		expected string
+	}{
+		{Stack(logo, plus, TopRight, 0.5), `viewBox="0 0 32 32" fill="currentColor"><path d="M1 1"/><g transform="translate(16 0) scale(1)"`},
+		{Badge(logo, 3), `<circle cx="22.4" cy="9.6" r="11.6" fill="#000"/>`},
+		{Badge(logo, 3), `<circle cx="22.4" cy="9.6" r="9.6" fill="#ef4444"/>`},
+	} {
+		if result := makeSVGTagContext(WithIDScope(context.Background()), tt.builder.GetIcon()); !strings.Contains(result, tt.expected) {
+			t.Errorf("makeSVGTagContext() = %q, want %q", result, tt.expected)
+		}
+	}
+}
+
+func TestStack_DuotoneKnockout(t *testing.T) {
+	bell := Bell.Config().Duotone("", "", 0.3).GetIcon()
+	plus := &Icon{Name: "plus-circle-16-solid", Size: "16", Type: "Micro", body: `<path d="M2 2"/>`}
+
+	// The knockout cuts the overlay out of the Solid underlay too
+	for _, builder := range []*IconBuilder{Badge(bell, 3), Stack(bell, plus, BottomRight, 0.5, WithKnockout(1))} {
+		result := makeSVGTagContext(WithIDScope(context.Background()), builder.GetIcon())
+		if expected := `<g mask="url(#templheroicons-mask-1)"><g opacity="0.3" stroke="none">`; !strings.Contains(result, expected) {
+			t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+		}
+	}
+}
+
+func TestStack_ContextSource(t *testing.T) {
+	ctx := WithSource(WithIDScope(context.Background()), MapSource{
+		"logo": {SVG: `<path d="M1 1"/>`, ViewBox: 32},
+		"dot":  {SVG: `<circle cx="8" cy="8" r="8"/>`, ViewBox: 16},
+	})
+	logo := &Icon{Name: "logo", Type: "Solid", Size: "24"}
+	dot := &Icon{Name: "dot", Type: "Solid", Size: "24"}
+
+	// The base and overlay bodies are looked up in the source of the context
+	for _, tt := range []struct {
+		builder  *IconBuilder
+		expected string
+	}{
+		{Stack(logo, dot, TopRight, 0.5), `viewBox="0 0 32 32" fill="currentColor"><path d="M1 1"/><g transform="translate(16 0) scale(1)" fill="currentColor"><circle cx="8" cy="8" r="8"/></g></svg>`},
+		{Badge(logo, 3), `<circle cx="22.4" cy="9.6" r="9.6" fill="#ef4444"/>`},
+	} {
+		if result := makeSVGTagContext(ctx, tt.builder.GetIcon()); !strings.Contains(result, tt.expected) {
+			t.Errorf("makeSVGTagContext() = %q, want %q", result, tt.expected)
+		}
+	}
+}
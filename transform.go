@@ -0,0 +1,81 @@
+package templheroicons
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Rotate rotates the icon clockwise by the given degrees around its center.
+// Rotations by 90° and 270° swap the width and height of the icon.
+func (b *IconBuilder) Rotate(degrees float64) *IconBuilder {
+	if math.IsNaN(degrees) || math.IsInf(degrees, 0) {
+		b.icon.setError(fmt.Errorf("invalid rotation %v", degrees))
+		return b
+	}
+	b.icon.rotate = normalizeDegrees(b.icon.rotate + degrees)
+	return b
+}
+
+// FlipH flips the icon horizontally. Flipping twice restores the icon.
+func (b *IconBuilder) FlipH() *IconBuilder {
+	b.icon.flipH = !b.icon.flipH
+	return b
+}
+
+// FlipV flips the icon vertically. Flipping twice restores the icon.
+func (b *IconBuilder) FlipV() *IconBuilder {
+	b.icon.flipV = !b.icon.flipV
+	return b
+}
+
+// normalizeDegrees returns the angle in the [0, 360) range.
+func normalizeDegrees(degrees float64) float64 {
+	degrees = math.Mod(degrees, 360)
+	if degrees < 0 {
+		degrees += 360
+	}
+	return degrees
+}
+
+// getTransform returns the transform applied to the icon body, relative to its viewBox,
+// following the Iconify rotate, hFlip and vFlip model: flips are applied first, then
+// the rotation around the center of the viewBox.
+func getTransform(icon *Icon) string {
+	viewBox := icon.viewBoxSize()
+	width, height := formatFloat(viewBox), formatFloat(viewBox)
+
+	var transforms []string
+	if icon.rotate != 0 {
+		center := formatFloat(viewBox / 2)
+		transforms = append(transforms, fmt.Sprintf("rotate(%s %s %s)", formatFloat(icon.rotate), center, center))
+	}
+	if icon.flipH {
+		transforms = append(transforms, fmt.Sprintf("translate(%s 0)", width), "scale(-1 1)")
+	}
+	if icon.flipV {
+		transforms = append(transforms, fmt.Sprintf("translate(0 %s)", height), "scale(1 -1)")
+	}
+	return strings.Join(transforms, " ")
+}
+
+// getMirrorTransform returns the transform mirroring the transformed icon for a right-to-left context.
+func getMirrorTransform(icon *Icon) string {
+	if !icon.mirror {
+		return ""
+	}
+	return fmt.Sprintf("translate(%s 0) scale(-1 1)", formatFloat(icon.viewBoxSize()))
+}
+
+// isQuarterTurn reports whether the rotation swaps the width and height of the icon.
+func isQuarterTurn(degrees float64) bool {
+	return degrees == 90 || degrees == 270
+}
+
+// wrapTransform wraps the body in a group applying the transform.
+func wrapTransform(body, transform string) string {
+	if transform == "" {
+		return body
+	}
+	return fmt.Sprintf(`<g transform="%s">%s</g>`, transform, body)
+}
@@ -0,0 +1,80 @@
+package templheroicons
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTransform_Builder(t *testing.T) {
+	icon := &Icon{Name: "chevron-down", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+	mini := &Icon{Name: "chevron-down-20-solid", Size: "20", Type: "Mini", body: `<path d="M1 1"/>`}
+
+	tests := []struct {
+		name     string
+		builder  *IconBuilder
+		expected string
+	}{
+		{
+			name:     "Rotate 90",
+			builder:  icon.Config().Rotate(90),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="rotate(90 12 12)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Rotate -90 is normalized",
+			builder:  mini.Config().Rotate(-90),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20" fill="currentColor"><g transform="rotate(270 10 10)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Rotate 360 is a no-op",
+			builder:  icon.Config().Rotate(180).Rotate(180),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Quarter turn swaps width and height",
+			builder:  icon.Config().SetWidth("48").SetHeight("24").Rotate(90),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="48" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="rotate(90 12 12)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Half turn keeps width and height",
+			builder:  icon.Config().SetWidth("48").SetHeight("24").Rotate(180),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="rotate(180 12 12)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Flip horizontally",
+			builder:  icon.Config().FlipH(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><g transform="translate(24 0) scale(-1 1)"><path d="M1 1"/></g></svg>`,
+		},
+		{
+			name:     "Flip twice restores the icon",
+			builder:  icon.Config().FlipV().FlipV(),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="24" height="24" viewBox="0 0 24 24" fill="none" stroke-width="1.5" stroke="currentColor"><path d="M1 1"/></svg>`,
+		},
+		{
+			name:     "Flips are applied before the rotation",
+			builder:  mini.Config().FlipH().FlipV().Rotate(45),
+			expected: `<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 20 20" fill="currentColor"><g transform="rotate(45 10 10) translate(20 0) scale(-1 1) translate(0 20) scale(1 -1)"><path d="M1 1"/></g></svg>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := makeSVGTag(tt.builder.GetIcon()); result != tt.expected {
+				t.Errorf("makeSVGTag() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+
+	if icon.rotate != 0 || icon.flipH || icon.flipV {
+		t.Errorf("original icon modified")
+	}
+}
+
+func TestTransform_InvalidRotation(t *testing.T) {
+	icon := &Icon{Name: "chevron-down", Size: "24", Type: "Outline", body: `<path d="M1 1"/>`}
+
+	result := makeSVGTag(icon.Config().Rotate(math.Inf(1)).GetIcon())
+	if !strings.HasPrefix(result, "<!-- Error: invalid rotation") {
+		t.Errorf("expected error comment, got %q", result)
+	}
+}
@@ -0,0 +1,83 @@
+package templheroicons
+
+import (
+	"math"
+	"strings"
+)
+
+// iconVariant describes a designed variant of the Solid icon family.
+type iconVariant struct {
+	suffix   string  // Name suffix in the dataset (e.g., "-20-solid")
+	iconType string  // Icon type (e.g., "Mini")
+	size     float64 // Designed size in pixels
+}
+
+// solidVariants lists the Solid family variants, from the largest to the smallest.
+// Outline icons are only designed at 24px and have no smaller variants.
+var solidVariants = []iconVariant{
+	{suffix: "-solid", iconType: "Solid", size: 24},
+	{suffix: "-20-solid", iconType: "Mini", size: 20},
+	{suffix: "-16-solid", iconType: "Micro", size: 16},
+}
+
+// SetSizeAuto sets the size of the icon and switches to the designed variant closest
+// to it, e.g. SetSizeAuto(16) on a Solid icon renders the hand-tuned Micro glyph.
+// The variant is looked up when rendering, in the source of the context (see WithSource).
+// Outline icons, duotone and composed icons (see Duotone, Stack and Badge) and icons without
+// the matching variant keep their artwork.
+func (b *IconBuilder) SetSizeAuto(size int) *IconBuilder {
+	b.SetSize(size)
+	if b.icon.duotone != nil || len(b.icon.compositions) > 0 || b.icon.viewBox != 0 {
+		return b // Duotone and composed icons, and icons created with NewIcon have no variants
+	}
+	if _, ok := solidFamilyBaseName(b.icon); ok {
+		b.icon.autoSize = size
+	}
+	return b
+}
+
+// resolveVariant switches the icon to the variant of the source closest to the size
+// set with SetSizeAuto.
+func (i *Icon) resolveVariant(source Source) {
+	base, ok := solidFamilyBaseName(i)
+	if i.autoSize == 0 || !ok {
+		return
+	}
+
+	for _, variant := range closestVariants(float64(i.autoSize)) {
+		name := base + variant.suffix
+		if name == i.Name {
+			return // The icon is already the closest variant
+		}
+		if body, err := source.Body(name); err == nil {
+			i.Name = name
+			i.Type = variant.iconType
+			i.setBody(body)
+			return
+		}
+	}
+}
+
+// solidFamilyBaseName returns the icon name without its variant suffix,
+// if the icon belongs to the Solid family.
+func solidFamilyBaseName(icon *Icon) (string, bool) {
+	for _, variant := range solidVariants {
+		if icon.Type == variant.iconType && strings.HasSuffix(icon.Name, variant.suffix) {
+			return strings.TrimSuffix(icon.Name, variant.suffix), true
+		}
+	}
+	return "", false
+}
+
+// closestVariants returns the Solid family variants sorted by distance to the size.
+// On ties, the larger variant comes first since downscaling keeps details crisp.
+func closestVariants(size float64) []iconVariant {
+	variants := make([]iconVariant, len(solidVariants))
+	copy(variants, solidVariants)
+	for i := 1; i < len(variants); i++ {
+		for j := i; j > 0 && math.Abs(variants[j].size-size) < math.Abs(variants[j-1].size-size); j-- {
+			variants[j], variants[j-1] = variants[j-1], variants[j]
+		}
+	}
+	return variants
+}
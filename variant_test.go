@@ -0,0 +1,90 @@
+package templheroicons
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+func TestVariant_SetSizeAuto(t *testing.T) {
+	tests := []struct {
+		name         string
+		icon         *Icon
+		size         int
+		expectedName string
+		expectedType string
+	}{
+		{"Solid at 16 uses Micro", AcademicCapSolid, 16, "academic-cap-16-solid", "Micro"},
+		{"Solid at 17 uses Micro", AcademicCapSolid, 17, "academic-cap-16-solid", "Micro"},
+		{"Solid at 18 prefers the larger Mini", AcademicCapSolid, 18, "academic-cap-20-solid", "Mini"},
+		{"Solid at 20 uses Mini", AcademicCapSolid, 20, "academic-cap-20-solid", "Mini"},
+		{"Solid at 22 uses Solid", AcademicCapSolid, 22, "academic-cap-solid", "Solid"},
+		{"Solid at 48 uses Solid", AcademicCapSolid, 48, "academic-cap-solid", "Solid"},
+		{"Micro at 24 uses Solid", AcademicCapMicro, 24, "academic-cap-solid", "Solid"},
+		{"Mini at 12 uses Micro", AcademicCapMini, 12, "academic-cap-16-solid", "Micro"},
+		{"Outline keeps its artwork", AcademicCap, 16, "academic-cap", "Outline"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The variant is picked when rendering
+			icon, err := tt.icon.Config().SetSizeAuto(tt.size).GetIcon().withBody(context.Background())
+			if err != nil {
+				t.Fatalf("withBody() error = %v", err)
+			}
+			if icon.Name != tt.expectedName || icon.Type != tt.expectedType {
+				t.Errorf("SetSizeAuto(%d) = %s (%s), want %s (%s)", tt.size, icon.Name, icon.Type, tt.expectedName, tt.expectedType)
+			}
+			if icon.Size.String() != fmt.Sprint(tt.size) {
+				t.Errorf("SetSizeAuto(%d) size = %s", tt.size, icon.Size)
+			}
+		})
+	}
+
+	// The original icon is unchanged
+	if AcademicCapSolid.Name != "academic-cap-solid" || AcademicCapSolid.Size != Size24 {
+		t.Errorf("original icon modified: %+v", AcademicCapSolid)
+	}
+}
+
+func TestVariant_SetSizeAutoMissingVariant(t *testing.T) {
+	// Only the Solid and Mini variants exist
+	SetSource(SourceFunc(func(name string) (Body, error) {
+		switch name {
+		case "custom-solid", "custom-20-solid":
+			return Body{SVG: `<path d="M1 1"/>`}, nil
+		}
+		return Body{}, fmt.Errorf("icon '%s' not found", name)
+	}))
+	defer SetSource(nil)
+
+	icon := &Icon{Name: "custom-solid", Type: "Solid", Size: "24"}
+	result, err := icon.Config().SetSizeAuto(16).GetIcon().withBody(context.Background())
+	if err != nil {
+		t.Fatalf("withBody() error = %v", err)
+	}
+	if result.Name != "custom-20-solid" || result.Type != "Mini" {
+		t.Errorf("SetSizeAuto(16) = %s (%s), want custom-20-solid (Mini)", result.Name, result.Type)
+	}
+}
+
+func TestVariant_SetSizeAutoContextSource(t *testing.T) {
+	icon := AcademicCapSolid.Config().SetSizeAuto(16).GetIcon()
+
+	// The variant is looked up in the source of the context
+	ctx := WithSource(context.Background(), MapSource{
+		"academic-cap-solid":    {SVG: `<path d="M1 1"/>`},
+		"academic-cap-16-solid": {SVG: `<path d="M2 2"/>`},
+	})
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" viewBox="0 0 16 16" fill="currentColor"><path d="M2 2"/></svg>`
+	if result := makeSVGTagContext(ctx, icon); result != expected {
+		t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+	}
+
+	// Without the Micro variant in the context source, the Solid artwork is kept
+	ctx = WithSource(context.Background(), MapSource{"academic-cap-solid": {SVG: `<path d="M1 1"/>`}})
+	expected = `<svg xmlns="http://www.w3.org/2000/svg" width="16" height="16" viewBox="0 0 24 24" fill="currentColor"><path d="M1 1"/></svg>`
+	if result := makeSVGTagContext(ctx, icon); result != expected {
+		t.Errorf("makeSVGTagContext() = %q, want %q", result, expected)
+	}
+}